@@ -0,0 +1,291 @@
+package vsf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggregateFunc is a function computing a footer cell from the cells of
+// a column.
+type AggregateFunc int
+
+const (
+	// AggSum adds up the numbers of the column.
+	AggSum AggregateFunc = iota
+	// AggAvg averages the numbers of the column.
+	AggAvg
+	// AggMin is the smallest number of the column.
+	AggMin
+	// AggMax is the largest number of the column.
+	AggMax
+	// AggCount counts the non-empty cells of the column.
+	AggCount
+)
+
+// String returns the name accepted by ParseAggregates.
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggSum:
+		return "sum"
+	case AggAvg:
+		return "avg"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	case AggCount:
+		return "count"
+	}
+	return fmt.Sprintf("AggregateFunc(%d)", int(f))
+}
+
+// Aggregate is a footer cell: Func applied to the data rows of Column.
+type Aggregate struct {
+	Func   AggregateFunc
+	Column ColumnRef
+}
+
+// ParseAggregates parses comma-separated FUNC:COLUMN pairs, with FUNC one
+// of sum, avg, min, max and count and COLUMN a 1-based column number or a
+// header name.
+//
+// Example:
+//
+//	ParseAggregates("sum:3,count:name")
+//	// [{AggSum, {Index: 2}}, {AggCount, {Name: "name"}}]
+func ParseAggregates(spec string) ([]Aggregate, error) {
+	var aggs []Aggregate
+	for _, part := range strings.Split(spec, ",") {
+		name, column, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid aggregate %q: want FUNC:COLUMN", part)
+		}
+
+		agg := Aggregate{Func: -1}
+		for _, f := range []AggregateFunc{AggSum, AggAvg, AggMin, AggMax, AggCount} {
+			if name == f.String() {
+				agg.Func = f
+			}
+		}
+		if agg.Func < 0 {
+			return nil, fmt.Errorf("invalid aggregate %q: unknown function %s", part, name)
+		}
+
+		ref, err := ParseColumnRef(column)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregate %q: %w", part, err)
+		}
+		agg.Column = ref
+		aggs = append(aggs, agg)
+	}
+	return aggs, nil
+}
+
+// AddFooter appends footer rows with the aggregates of the data rows,
+// below a separator drawn with sepChar across the table, or none when
+// sepChar is empty. Each function gets a footer row, labelled with its
+// name in the first cell free of aggregates, holding its aggregates of
+// every column; a column aggregated twice by the same function gets a
+// second row.
+func (t *Table) AddFooter(sepChar string, aggs ...Aggregate) error {
+	footer, err := t.footer(t.Rows, sepChar, aggs)
+	if err != nil {
+		return err
+	}
+	t.Rows = append(t.Rows, footer...)
+	return nil
+}
+
+// footer returns the separator and footer rows with the aggregates of
+// the data rows among rows. See AddFooter.
+func (t *Table) footer(rows []Row, sepChar string, aggs []Aggregate) ([]Row, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	type footerRow struct {
+		f     AggregateFunc
+		cells []string
+		used  map[int]bool
+	}
+	var (
+		lines []footerRow
+		width = len(t.Widths())
+	)
+	for _, agg := range aggs {
+		column, err := agg.Column.resolve(t)
+		if err != nil {
+			return nil, err
+		}
+		value := aggregate(rows, agg.Func, column, columnFormat(t.Rows, column))
+
+		line := 0
+		for line < len(lines) && (lines[line].f != agg.Func || lines[line].used[column]) {
+			line++
+		}
+		if line == len(lines) {
+			lines = append(lines, footerRow{f: agg.Func, used: map[int]bool{}})
+		}
+		l := &lines[line]
+		for len(l.cells) <= column {
+			l.cells = append(l.cells, "")
+		}
+		l.cells[column] = value
+		l.used[column] = true
+		width = max(width, column+1)
+	}
+
+	var footer []Row
+	if sepChar != "" {
+		footer = append(footer, Row{Kind: RowSeparator, Line: -1, Sep: sepChar})
+	}
+	for _, l := range lines {
+		for i := range width {
+			if !l.used[i] {
+				for len(l.cells) <= i {
+					l.cells = append(l.cells, "")
+				}
+				l.cells[i] = l.f.String()
+				break
+			}
+		}
+		footer = append(footer, Row{Kind: RowFooter, Cells: l.cells, Line: -1})
+	}
+	return footer, nil
+}
+
+// aggregate computes f over column of the data rows among rows, writing
+// the result with nf. Without any number it returns "", except for
+// AggCount.
+func aggregate(rows []Row, f AggregateFunc, column int, nf numberFormat) string {
+	var (
+		values []float64
+		count  int
+	)
+	for _, row := range rows {
+		if row.Kind != RowData {
+			continue
+		}
+		cell := cellAt(row, column)
+		if cell != "" {
+			count++
+		}
+		if n, ok := parseNumber(cell); ok {
+			values = append(values, n)
+		}
+	}
+
+	if f == AggCount {
+		return strconv.Itoa(count)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	result := values[0]
+	switch f {
+	case AggSum, AggAvg:
+		result = 0
+		for _, n := range values {
+			result += n
+		}
+		if f == AggAvg {
+			result /= float64(len(values))
+		}
+	case AggMin:
+		for _, n := range values {
+			result = min(result, n)
+		}
+	case AggMax:
+		for _, n := range values {
+			result = max(result, n)
+		}
+	}
+	if f == AggAvg {
+		// Rounded to the column's decimals, the mean of 1 and 2 would
+		// read 2
+		return nf.formatExtra(result, 2)
+	}
+	return nf.format(result)
+}
+
+// numberFormat is the way the numbers of a column are written.
+type numberFormat struct {
+	// decimals is the largest number of decimals of the values.
+	decimals int
+	// grouped reports that values use "," as thousands separator.
+	grouped bool
+	// percent reports that every value ends with "%".
+	percent bool
+}
+
+// columnFormat learns the format of the numbers in column of the data
+// rows among rows.
+func columnFormat(rows []Row, column int) numberFormat {
+	nf := numberFormat{percent: true}
+	numbers := 0
+	for _, row := range rows {
+		cell := strings.TrimSpace(cellAt(row, column))
+		if _, ok := parseNumber(cell); row.Kind != RowData || !ok {
+			continue
+		}
+		numbers++
+		nf.percent = nf.percent && strings.HasSuffix(cell, "%")
+		nf.grouped = nf.grouped || strings.Contains(cell, ",")
+		if _, frac, ok := strings.Cut(strings.TrimSuffix(cell, "%"), "."); ok {
+			nf.decimals = max(nf.decimals, len(frac))
+		}
+	}
+	nf.percent = nf.percent && numbers > 0
+	return nf
+}
+
+// format writes n with the decimals, grouping and percent sign of nf.
+func (nf numberFormat) format(n float64) string {
+	return nf.formatExtra(n, 0)
+}
+
+// formatExtra is format with up to extra more decimals, as many as n
+// needs.
+func (nf numberFormat) formatExtra(n float64, extra int) string {
+	s := strconv.FormatFloat(n, 'f', nf.decimals+extra, 64)
+	if extra > 0 {
+		integer, frac, _ := strings.Cut(s, ".")
+		frac = strings.TrimRight(frac, "0")
+		frac += strings.Repeat("0", max(nf.decimals-len(frac), 0))
+		s = integer
+		if frac != "" {
+			s += "." + frac
+		}
+	}
+	if nf.grouped {
+		s = groupThousands(s)
+	}
+	if nf.percent {
+		s += "%"
+	}
+	return s
+}
+
+// groupThousands inserts "," between groups of three digits of the
+// integer part of a formatted number.
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, frac, hasFrac := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFrac {
+		b.WriteString("." + frac)
+	}
+	return sign + b.String()
+}
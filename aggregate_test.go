@@ -0,0 +1,100 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAggregates(t *testing.T) {
+	got, err := ParseAggregates("sum:3, count:name")
+	if err != nil {
+		t.Fatalf("ParseAggregates() error = %v", err)
+	}
+	want := []Aggregate{{AggSum, ColumnRef{Index: 2}}, {AggCount, ColumnRef{Name: "name"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAggregates() = %+v, want %+v", got, want)
+	}
+
+	for _, spec := range []string{"sum", "median:1", "sum:0"} {
+		if _, err := ParseAggregates(spec); err == nil {
+			t.Errorf("ParseAggregates(%q) expected error", spec)
+		}
+	}
+}
+
+func TestTableAddFooter(t *testing.T) {
+	tests := []struct {
+		name string
+		aggs []Aggregate
+		want [][]string
+	}{
+		{
+			name: "Precision follows the column",
+			aggs: []Aggregate{{AggSum, ColumnRef{Index: 1}}, {AggAvg, ColumnRef{Index: 2}}},
+			want: [][]string{{"sum", "1,011.50"}, {"avg", "", "47.5%"}},
+		},
+		{
+			name: "Labels in the first free cell",
+			aggs: []Aggregate{{AggCount, ColumnRef{Index: 0}}, {AggMin, ColumnRef{Index: 1}}, {AggMax, ColumnRef{Index: 1}}, {AggCount, ColumnRef{Index: 1}}},
+			want: [][]string{{"3", "3", "count"}, {"min", "1.00"}, {"max", "1,000.00"}},
+		},
+		{
+			name: "No numbers",
+			aggs: []Aggregate{{AggSum, ColumnRef{Name: "name"}}},
+			want: [][]string{{"", "sum"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := ParseTable("name:cost:share\napi:1,000:50%\nweb:10.5:45%\ndb:1.00:", ":")
+			table.MarkHeader(1)
+			if err := table.AddFooter("-", tt.aggs...); err != nil {
+				t.Fatalf("AddFooter() error = %v", err)
+			}
+
+			sep := table.Rows[4]
+			if sep.Kind != RowSeparator || sep.Sep != "-" || sep.Cells != nil {
+				t.Errorf("separator = %+v, want a full width separator", sep)
+			}
+			var got [][]string
+			for _, row := range table.Rows[5:] {
+				if row.Kind != RowFooter {
+					t.Errorf("Kind = %v, want footer", row.Kind)
+				}
+				got = append(got, row.Cells)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("footer = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateAvg(t *testing.T) {
+	table, _ := ParseTable("a:1\nb:2\nc:2.0\nd:2.5", ":")
+	tests := []struct {
+		rows []Row
+		want string
+	}{
+		{table.Rows[:2], "1.5"},
+		{table.Rows[1:3], "2.0"},
+		{table.Rows[:4], "1.875"},
+	}
+	for _, tt := range tests {
+		if got := aggregate(tt.rows, AggAvg, 1, columnFormat(tt.rows, 1)); got != tt.want {
+			t.Errorf("aggregate(avg) = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFormatterFooter(t *testing.T) {
+	f, _ := New(WithOutputDelimiter("|"), WithFooter("=", Aggregate{AggSum, ColumnRef{Index: 1}}))
+	got, err := f.Format("a:10\nb:5\nc:1000")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "a   | 10\nb   | 5\nc   | 1000\n====|=====\nsum | 1015"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package vsf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// block is a run of input lines aligned as a table of its own, or a run
+// of blank lines between tables, written as-is.
+type block struct {
+	text  string
+	blank bool
+}
+
+// splitBlocks splits input into blocks at blank lines, which are kept as
+// blank blocks, and before every line matching start, when set. Leading
+// and trailing blank lines are dropped, as the input is trimmed when it
+// is parsed as a whole.
+//
+// Example:
+//
+//	splitBlocks("a:b\n\nc:d", nil)
+//	// [{"a:b", false}, {"", true}, {"c:d", false}]
+func splitBlocks(input string, start *regexp.Regexp) []block {
+	lines := strings.Split(input, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var (
+		blocks  []block
+		current []string
+		blank   bool
+	)
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, block{text: strings.Join(current, "\n"), blank: blank})
+		}
+		current = nil
+	}
+
+	for _, line := range lines {
+		isBlank := strings.TrimSpace(line) == ""
+		if isBlank != blank || (!isBlank && start != nil && start.MatchString(line)) {
+			flush()
+			blank = isBlank
+		}
+		current = append(current, line)
+	}
+	flush()
+	return blocks
+}
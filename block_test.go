@@ -0,0 +1,66 @@
+package vsf
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSplitBlocks(t *testing.T) {
+	got := splitBlocks("\na:b\nc:d\n\n\n[x]\ne:f\n[y]\n", regexp.MustCompile(`^\[`))
+	want := []block{
+		{text: "a:b\nc:d"},
+		{text: "\n", blank: true},
+		{text: "[x]\ne:f"},
+		{text: "[y]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitBlocks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormatterBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Blank lines",
+			opts: []Option{WithBlocks()},
+			want: "name     = john\nlastname = doe\n\n\nage  = 30\n[db]\nhost = local",
+		},
+		{
+			name: "Block start",
+			opts: []Option{WithBlockStart(regexp.MustCompile(`^\[`))},
+			want: "name     = john\nlastname = doe\n\n\nage = 30\n[db]\nhost = local",
+		},
+		{
+			name: "Line numbers count per block",
+			opts: []Option{WithBlocks(), WithSeparator(0, "-")},
+			want: "name     = john\n---------=-----\nlastname = doe\n\n\nage  = 30\n-----=---\n[db]\nhost = local",
+		},
+	}
+
+	const input = "name=john\nlastname=doe\n\n\nage=30\n[db]\nhost=local\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(append([]Option{WithDelimiter("="), WithOutputDelimiter("=")}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := f.Format(input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	f, _ := New(WithBlocks())
+	if _, err := f.Format("\n \n"); err != ErrEmptyInput {
+		t.Errorf("Format() error = %v, want ErrEmptyInput", err)
+	}
+}
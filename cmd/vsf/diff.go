@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// edit is a line of an edit script: kept (' '), deleted ('-') or
+// inserted ('+').
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff turning a into b, labeled with
+// the names of the old and new file, or "" when they are equal.
+func unifiedDiff(oldName, newName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Line numbers in a and b at the start of each edit
+	aLine := make([]int, len(edits)+1)
+	bLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.op != '+' {
+			aLine[i+1]++
+		}
+		if e.op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := start + 1
+		for i := end; i < len(edits); i++ {
+			if edits[i].op == ' ' {
+				continue
+			}
+			if i-end > 2*diffContext {
+				break
+			}
+			end = i + 1
+		}
+		from := max(start-diffContext, 0)
+		to := min(end+diffContext, len(edits))
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(aLine[from], aLine[to]-aLine[from]),
+			hunkRange(bLine[from], bLine[to]-bLine[from]))
+		for _, e := range edits[from:to] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange formats the 0-based start and length of a hunk side.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// splitLines splits s into lines that keep their newline. A last line
+// without one is marked as in diff output.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+	return lines
+}
+
+// diffLines computes a shortest edit script between a and b with Myers'
+// algorithm in linear space, so large files diff without a table of
+// len(a) × len(b) entries. Lines found on one side only are edits in any
+// script, so the search only runs over the others.
+func diffLines(a, b []string) []edit {
+	shared := func(lines, other []string) (kept []string, index []int) {
+		seen := make(map[string]bool, len(other))
+		for _, line := range other {
+			seen[line] = true
+		}
+		for i, line := range lines {
+			if seen[line] {
+				kept = append(kept, line)
+				index = append(index, i)
+			}
+		}
+		return kept, index
+	}
+	sharedA, indexA := shared(a, b)
+	sharedB, indexB := shared(b, a)
+
+	d := differ{a: sharedA, b: sharedB}
+	d.diff(0, len(sharedA), 0, len(sharedB))
+
+	// Lines kept by the search anchor the script, everything between
+	// them is deleted from a then inserted from b
+	edits := make([]edit, 0, max(len(a), len(b)))
+	i, j, x, y := 0, 0, 0, 0
+	flush := func(toA, toB int) {
+		for ; i < toA; i++ {
+			edits = append(edits, edit{'-', a[i]})
+		}
+		for ; j < toB; j++ {
+			edits = append(edits, edit{'+', b[j]})
+		}
+	}
+	for _, e := range d.edits {
+		switch e.op {
+		case ' ':
+			flush(indexA[x], indexB[y])
+			edits = append(edits, edit{' ', a[i]})
+			i, j = i+1, j+1
+			x, y = x+1, y+1
+		case '-':
+			x++
+		case '+':
+			y++
+		}
+	}
+	flush(len(a), len(b))
+	return edits
+}
+
+// differ holds the lines being compared and the edit script built so far.
+type differ struct {
+	a, b  []string
+	edits []edit
+}
+
+// diff appends the edits turning a[aLo:aHi] into b[bLo:bHi], splitting
+// the ranges at their middle snake until one side is empty.
+func (d *differ) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, edit{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := aHi
+	for aHi > aLo && bHi > bLo && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case !d.shareLines(aLo, aHi, bLo, bHi):
+		// Like a file formatted for the first time, where every line
+		// changes: the search would find nothing, slowly
+		for _, line := range d.a[aLo:aHi] {
+			d.edits = append(d.edits, edit{'-', line})
+		}
+		for _, line := range d.b[bLo:bHi] {
+			d.edits = append(d.edits, edit{'+', line})
+		}
+	default:
+		// Without a common prefix or suffix both halves hold edits, so
+		// the recursion ends. Empty ranges share no lines.
+		x, y := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.diff(aLo, x, bLo, y)
+		d.diff(x, aHi, y, bHi)
+	}
+
+	for _, line := range d.a[aHi:suffix] {
+		d.edits = append(d.edits, edit{' ', line})
+	}
+}
+
+// shareLines reports whether a[aLo:aHi] and b[bLo:bHi] have a line in
+// common.
+func (d *differ) shareLines(aLo, aHi, bLo, bHi int) bool {
+	seen := make(map[string]bool, aHi-aLo)
+	for _, line := range d.a[aLo:aHi] {
+		seen[line] = true
+	}
+	for _, line := range d.b[bLo:bHi] {
+		if seen[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// middleSnake runs the search for a shortest edit script from both ends
+// of the ranges at once, and returns where the paths meet: the start of
+// the snake, the run of equal lines, found in the middle of the script.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward[k] is the furthest x reached on diagonal x-y = k from the
+	// start, backward[k] the same from the end, counting back.
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+	for depth := 0; depth <= limit; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			x := forward[offset+k-1] + 1
+			if k == -depth || (k != depth && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			}
+			startX, startY := x, x-k
+			for y := x - k; x < n && y < m && d.a[aLo+x] == d.b[bLo+y]; y++ {
+				x++
+			}
+			forward[offset+k] = x
+
+			if back := delta - k; delta%2 != 0 && back >= -(depth-1) && back <= depth-1 && x+backward[offset+back] >= n {
+				return aLo + startX, bLo + startY
+			}
+		}
+
+		for k := -depth; k <= depth; k += 2 {
+			x := backward[offset+k-1] + 1
+			if k == -depth || (k != depth && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			}
+			for y := x - k; x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y]; y++ {
+				x++
+			}
+			backward[offset+k] = x
+
+			if fwd := delta - k; delta%2 == 0 && fwd >= -depth && fwd <= depth && x+forward[offset+fwd] >= n {
+				return aHi - x, bHi - (x - k)
+			}
+		}
+	}
+	// The paths always meet within limit steps
+	panic("unreachable")
+}
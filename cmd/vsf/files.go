@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sisoe24/vsf"
+)
+
+// stdinName is the name reported for standard input by -l and -diff.
+const stdinName = "<standard input>"
+
+// process formats src, read from the file called name, and depending on
+// -l, -w and -diff lists, rewrites or diffs it. Without any of them the
+// formatted text is written to w. It reports whether formatting changed
+// src. Empty input is left as it is.
+func (o options) process(f *vsf.Formatter, name string, src []byte, w io.Writer) (bool, error) {
+	var b bytes.Buffer
+	err := f.Render(&b, string(src))
+	switch {
+	case errors.Is(err, vsf.ErrEmptyInput):
+		b.Reset()
+		b.Write(src)
+	case err != nil:
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+
+	res := b.Bytes()
+	changed := !bytes.Equal(src, res)
+	if !o.list && !o.write && !o.diff {
+		_, err := w.Write(res)
+		return changed, err
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if o.list {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return true, err
+		}
+	}
+	if o.write {
+		if err := writeFile(name, res); err != nil {
+			return true, err
+		}
+	}
+	if o.diff {
+		if _, err := io.WriteString(w, unifiedDiff(name+".orig", name, string(src), string(res))); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// writeFile replaces the contents of path atomically: data goes to a
+// temporary file in the same directory, which is renamed over path
+// with its permissions.
+func writeFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".vsf-*")
+	if err != nil {
+		return err
+	}
+	// Nothing left to remove once renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sisoe24/vsf"
+)
+
+func TestProcessFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "table.conf")
+	if err := os.WriteFile(path, []byte("a=1\nbbb=2\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	o := options{delimiter: "=", headerStyle: "none", input: "delim", output: "text", undelimited: "passthrough", section: "center", sepAfter: -1, number: -1, code: true}
+	f, err := o.formatter()
+	if err != nil {
+		t.Fatalf("formatter() error = %v", err)
+	}
+	const want = "a   = 1\nbbb = 2\n"
+
+	var out strings.Builder
+	o.list = true
+	src, _ := os.ReadFile(path)
+	if changed, err := o.process(f, path, src, &out); err != nil || !changed || out.String() != path+"\n" {
+		t.Errorf("process(-l) = %v, %v, output %q", changed, err, out.String())
+	}
+
+	o.list, o.write = false, true
+	if _, err := o.process(f, path, src, &out); err != nil {
+		t.Fatalf("process(-w) error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	info, _ := os.Stat(path)
+	if string(got) != want || info.Mode().Perm() != 0o640 {
+		t.Errorf("file = %q, mode %v, want %q, mode 0640", got, info.Mode().Perm(), want)
+	}
+
+	if changed, _ := o.process(f, path, got, &out); changed {
+		t.Error("process() reports a formatted file as changed")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("ReadDir() = %v, want only the rewritten file", entries)
+	}
+}
+
+func TestProcessFilesKeepDelimiter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "table.txt")
+	if err := os.WriteFile(path, []byte("a:1\nbbb:2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	o := options{delimiter: ":", headerStyle: "none", input: "delim", output: "text", undelimited: "passthrough", section: "center", sepAfter: -1, number: -1, write: true}
+	f, err := o.formatter()
+	if err != nil {
+		t.Fatalf("formatter() error = %v", err)
+	}
+
+	var out strings.Builder
+	for range 2 {
+		src, _ := os.ReadFile(path)
+		if _, err := o.process(f, path, src, &out); err != nil {
+			t.Fatalf("process(-w) error = %v", err)
+		}
+	}
+	got, _ := os.ReadFile(path)
+	if want := "a   : 1\nbbb : 2\n"; string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+
+	// A new row lines up with the rows formatted before
+	os.WriteFile(path, append(got, "cc:3\n"...), 0o644)
+	src, _ := os.ReadFile(path)
+	if _, err := o.process(f, path, src, &out); err != nil {
+		t.Fatalf("process(-w) error = %v", err)
+	}
+	o.write, o.list = false, true
+	src, _ = os.ReadFile(path)
+	if changed, err := o.process(f, path, src, &out); err != nil || changed {
+		t.Errorf("process(-l) = %v, %v, want unchanged; file %q", changed, err, src)
+	}
+}
+
+func TestFileModeNeedsOutputDelimiter(t *testing.T) {
+	o := options{delimiter: vsf.AutoDelimiter, headerStyle: "none", input: "delim", output: "text", undelimited: "passthrough", section: "center", sepAfter: -1, number: -1, list: true}
+	if _, err := o.formatter(); err == nil {
+		t.Error("formatter() expected error for -l with -d auto and no -o")
+	}
+	o.outputDelimiter = "|"
+	if _, err := o.formatter(); err != nil {
+		t.Errorf("formatter() error = %v", err)
+	}
+}
+
+func TestProcessFilesDirectives(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "table.txt")
+	const src = "a:1\nbbb:2\n# vsf:off\nx:y\nlong:z\n# vsf:on\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	o := options{delimiter: ":", headerStyle: "none", input: "delim", output: "text", undelimited: "passthrough", section: "center", sepAfter: -1, number: -1, write: true}
+	f, err := o.formatter()
+	if err != nil {
+		t.Fatalf("formatter() error = %v", err)
+	}
+	if _, err := o.process(f, path, []byte(src), io.Discard); err != nil {
+		t.Fatalf("process(-w) error = %v", err)
+	}
+
+	const want = "a   : 1\nbbb : 2\n# vsf:off\nx:y\nlong:z\n# vsf:on\n"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -14,16 +16,94 @@ import (
 
 const VERSION = "1.3.0"
 
+// options holds the command line flags.
+type options struct {
+	delimiter       string
+	outputDelimiter string
+	sepAfter        int
+	sepChar         string
+	seps            listFlag
+	code            bool
+	directives      bool
+	blocks          bool
+	blockStart      string
+	skipLines       string
+	skipMatch       listFlag
+	comment         string
+	undelimited     string
+	sectionMatch    listFlag
+	section         string
+	header          string
+	headerMode      string
+	pageSize        int
+	headerStyle     string
+	input           string
+	output          string
+	align           string
+	sort            string
+	where           listFlag
+	footer          string
+	group           string
+	groupStyle      string
+	subtotal        string
+	squash          string
+	ditto           string
+	hiddenKey       bool
+	keyColumns      string
+	fzfOpts         bool
+	transpose       bool
+	number          int
+	verbose         bool
+	write           bool
+	list            bool
+	diff            bool
+}
+
 func main() {
 	var (
-		delimiter       = flag.String("d", ":", "Delimiter used.")
-		outputDelimiter = flag.String("o", "│", "Output text with selected delimiter")
-		sepAfter        = flag.Int("sep-after", -1, "Add separator after this line number (0-based)")
-		sepChar         = flag.String("sep-char", "═", "Character to use for separator line")
-		skipLines       = flag.String("skip", "", "Comma-separated line numbers to skip from width calculations (0-based)")
-		version         = flag.Bool("version", false, "Print current version")
-		usage           = flag.Bool("h", false, "Show usage information")
+		opts    options
+		version = flag.Bool("version", false, "Print current version")
+		usage   = flag.Bool("h", false, "Show usage information")
 	)
+	flag.StringVar(&opts.delimiter, "d", "", "Delimiter used (default \":\", \",\" with -input csv). A regular expression with -input regex, 'auto' to detect it")
+	flag.StringVar(&opts.outputDelimiter, "o", "", "Output text with selected delimiter (default \"│\", the input delimiter with -code, -w, -l and -diff)")
+	flag.IntVar(&opts.sepAfter, "sep-after", -1, "Add separator after this line number (0-based)")
+	flag.StringVar(&opts.sepChar, "sep-char", "═", "Character to use for separator line")
+	flag.Var(&opts.seps, "sep", "Add a separator: [after:|before:]LINE|/REGEX/[:CHAR], negative lines count from the end (repeatable)")
+	flag.BoolVar(&opts.code, "code", false, "Align source code: split at the first delimiter only, keep indentation and trailing comments (-comment prefix, or // and #), align lines with the same indentation")
+	flag.BoolVar(&opts.directives, "directives", false, "Only align regions between vsf:align-begin and vsf:align-end comments, skipping vsf:off to vsf:on (always on with -w, -l and -diff)")
+	flag.BoolVar(&opts.blocks, "blocks", false, "Align each block of lines separated by blank lines on its own")
+	flag.StringVar(&opts.blockStart, "block-start", "", "Also start a block at lines matching this regular expression (implies -blocks)")
+	flag.StringVar(&opts.skipLines, "skip", "", "Lines to skip from width calculations: 0-based numbers and ranges like 0-2,5-,-1 (negative count from the end)")
+	flag.Var(&opts.skipMatch, "skip-match", "Pass through lines matching this regular expression, leaving them out of width calculations (repeatable)")
+	flag.StringVar(&opts.comment, "comment", "", "Pass through comment lines starting with this prefix")
+	flag.StringVar(&opts.undelimited, "undelimited", "passthrough", "Lines without the delimiter: passthrough (verbatim, ignored for widths), aligned or section")
+	flag.Var(&opts.sectionMatch, "section-match", "Lay out lines matching this regular expression as section titles spanning the table (repeatable)")
+	flag.StringVar(&opts.section, "section", "center", "Section title layout: center, left, framed (drawn with -sep-char)")
+	flag.StringVar(&opts.header, "header", "", "Number of header lines to keep, or 'auto' to detect a header row")
+	flag.StringVar(&opts.headerMode, "header-mode", "", "Header layout: aligned, verbatim, excluded (default verbatim, aligned with -header auto)")
+	flag.IntVar(&opts.pageSize, "page", 0, "Repeat the header every N lines")
+	flag.StringVar(&opts.headerStyle, "header-style", "none", "Header style: none, bold, underline, upper")
+	flag.StringVar(&opts.input, "input", vsf.DefaultParser, "Input format: "+strings.Join(vsf.Parsers(), ", "))
+	flag.StringVar(&opts.output, "output", vsf.DefaultRenderer, "Output format: "+strings.Join(vsf.Renderers(), ", "))
+	flag.StringVar(&opts.align, "align", "", "Comma-separated column alignments: l(eft), r(ight), c(enter)")
+	flag.Var(&opts.where, "where", "Keep rows matching an expression like 'status != \"ok\" && $3 > 200': columns as $N (1-based) or header names, == != < <= > >= =~ !~ && || ! (repeatable)")
+	flag.StringVar(&opts.group, "group", "", "Group rows by this column: number (1-based) or header name")
+	flag.StringVar(&opts.groupStyle, "group-style", "section", "Set groups apart with a section title (see -section) or a blank line: section, blank")
+	flag.StringVar(&opts.subtotal, "subtotal", "", "Add subtotals below each group, aggregates as in -footer")
+	flag.StringVar(&opts.footer, "footer", "", "Add footer rows of comma-separated aggregates FUNC:COLUMN, FUNC one of sum, avg, min, max, count, one labelled row per function below a -sep-char separator")
+	flag.StringVar(&opts.sort, "sort", "", "Sort rows by comma-separated keys COLUMN[:TYPE][:desc]: column number (1-based) or header name, type s, n(umeric), v(ersion), h(uman size) or d(ate)")
+	flag.StringVar(&opts.squash, "squash", "", "Blank out cells equal to the cell above in these comma-separated columns, numbers (1-based) or header names, a column only when the ones before it are")
+	flag.StringVar(&opts.ditto, "ditto", "", "Write this mark in squashed cells instead of leaving them blank")
+	flag.BoolVar(&opts.hiddenKey, "hidden-key", false, "Start each line with a hidden copy of its source line followed by \\x1f, see -fzf-opts")
+	flag.StringVar(&opts.keyColumns, "key-columns", "", "Use the cells of these comma-separated columns, numbers (1-based) or header names, as hidden key fields (implies -hidden-key)")
+	flag.BoolVar(&opts.fzfOpts, "fzf-opts", false, "Print the fzf --delimiter and --with-nth options matching -hidden-key and -key-columns, and exit")
+	flag.BoolVar(&opts.transpose, "transpose", false, "Swap rows and columns, see also -output expanded")
+	flag.IntVar(&opts.number, "number", -1, "Prepend a right aligned index column numbering the data rows in input order from this value, usually 0 or 1, kept through -where and -sort")
+	flag.BoolVar(&opts.verbose, "v", false, "Report detected settings on stderr")
+	flag.BoolVar(&opts.write, "w", false, "Write the result to the file arguments instead of stdout")
+	flag.BoolVar(&opts.list, "l", false, "List files whose formatting differs, exit 1 if any")
+	flag.BoolVar(&opts.diff, "diff", false, "Print a unified diff of the formatting changes, exit 1 if any")
 
 	flag.Usage = showUsage
 	flag.Parse()
@@ -39,83 +119,304 @@ func main() {
 
 	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	var input strings.Builder
-	for scanner.Scan() {
-		input.WriteString(scanner.Text() + "\n")
+	formatter, err := opts.formatter()
+	if err != nil {
+		log.Fatal(err)
 	}
-	if err := scanner.Err(); err != nil {
-		log.Fatalf("Error reading input: %v", err)
+
+	if opts.fzfOpts {
+		fmt.Println(strings.Join(formatter.FzfArgs(), " "))
+		os.Exit(0)
 	}
 
-	inputStr := input.String()
-	var formattedOutput string
-	var err error
+	if opts.write && flag.NArg() == 0 {
+		log.Fatal("cannot use -w with standard input")
+	}
 
-	// Parse skip lines if provided
-	var skipLineNumbers []int
-	if *skipLines != "" {
-		skipLineNumbers, err = parseLineNumbers(*skipLines)
+	out := bufio.NewWriter(os.Stdout)
+	changed, failed := false, false
+	process := func(name string, src []byte) {
+		differs, err := opts.process(formatter, name, src, out)
 		if err != nil {
-			log.Fatalf("Error parsing skip lines: %v", err)
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+		changed = changed || differs
+	}
+
+	if flag.NArg() == 0 {
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("Error reading input: %v", err)
+		}
+		process(stdinName, src)
+	}
+	for _, path := range flag.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
 		}
+		process(path, src)
 	}
 
-	// Choose the appropriate formatting function
+	if err := out.Flush(); err != nil {
+		log.Fatal(err)
+	}
 	switch {
-	case *sepAfter >= 0:
-		// Add separator after specified line
-		formattedOutput, err = vsf.FormatWithSeparator(inputStr, *delimiter, *outputDelimiter, *sepAfter, *sepChar)
-	case len(skipLineNumbers) > 0:
-		// Skip certain lines from width calculations
-		formattedOutput, err = vsf.FormatSkipLines(inputStr, *delimiter, *outputDelimiter, skipLineNumbers)
-	default:
-		// Basic formatting (99% of cases)
-		formattedOutput, err = vsf.Format(inputStr, *delimiter, *outputDelimiter)
+	case failed:
+		os.Exit(2)
+	case changed && (opts.list || opts.diff):
+		os.Exit(1)
+	}
+}
+
+// formatter turns the command line flags into a formatter. Every flag
+// maps to its own option, so they all combine.
+func (o options) formatter() (*vsf.Formatter, error) {
+	// Files keep their input delimiter, so that formatting them again
+	// changes nothing.
+	outputDelimiter := o.outputDelimiter
+	switch {
+	case outputDelimiter != "" || o.code:
+	case o.fileMode() && (o.input != vsf.DefaultParser || o.delimiter == vsf.AutoDelimiter):
+		return nil, fmt.Errorf("-w, -l and -diff need -o unless the input delimiter is set with -d")
+	case !o.fileMode():
+		outputDelimiter = "│"
 	}
 
+	opts := []vsf.Option{
+		vsf.WithDelimiter(o.delimiter),
+		vsf.WithOutputDelimiter(outputDelimiter),
+		vsf.WithInput(o.input),
+		vsf.WithOutput(o.output),
+		vsf.WithPageSize(o.pageSize),
+	}
+
+	if o.verbose {
+		opts = append(opts, vsf.WithLogf(func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, format, args...)
+		}))
+	}
+
+	if o.code {
+		var comments []string
+		if o.comment != "" {
+			comments = append(comments, o.comment)
+		}
+		opts = append(opts, vsf.WithCode(comments...))
+	}
+	// Files always keep the lines between vsf:off and vsf:on, and the
+	// directives themselves, as they are
+	if o.directives || o.fileMode() {
+		opts = append(opts, vsf.WithDirectives())
+	}
+	if o.blockStart != "" {
+		re, err := regexp.Compile(o.blockStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block pattern: %w", err)
+		}
+		opts = append(opts, vsf.WithBlockStart(re))
+	} else if o.blocks {
+		opts = append(opts, vsf.WithBlocks())
+	}
+
+	skipLines, err := vsf.ParseLineSpec(o.skipLines)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("invalid skip lines: %w", err)
 	}
+	opts = append(opts, vsf.WithSkip(skipLines))
 
-	fmt.Print(formattedOutput)
-}
+	for _, pattern := range o.skipMatch {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid skip pattern: %w", err)
+		}
+		opts = append(opts, vsf.WithSkipMatch(re))
+	}
+	if o.comment != "" {
+		opts = append(opts, vsf.WithComment(o.comment))
+	}
 
-// parseLineNumbers parses comma-separated line numbers like "1,3,5"
-func parseLineNumbers(s string) ([]int, error) {
-	if s == "" {
-		return nil, nil
+	undelimited, err := vsf.ParseUndelimitedMode(o.undelimited)
+	if err != nil {
+		return nil, err
 	}
+	opts = append(opts, vsf.WithUndelimited(undelimited))
 
-	parts := strings.Split(s, ",")
-	lineNumbers := make([]int, 0, len(parts))
+	for _, pattern := range o.sectionMatch {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid section pattern: %w", err)
+		}
+		opts = append(opts, vsf.WithSectionMatch(re))
+	}
+	section, err := vsf.ParseSectionStyle(o.section)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, vsf.WithSectionStyle(section, o.sepChar))
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+	if o.sepAfter >= 0 {
+		opts = append(opts, vsf.WithSeparator(o.sepAfter, o.sepChar))
+	}
+	for _, spec := range o.seps {
+		sep, err := vsf.ParseSeparator(spec, o.sepChar)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, vsf.WithSeparators(sep))
+	}
+
+	switch o.header {
+	case "":
+	case "auto":
+		// Drive the header separator, unless one was placed explicitly
+		opts = append(opts, vsf.WithHeaderAuto())
+		if o.sepAfter < 0 && len(o.seps) == 0 {
+			opts = append(opts, vsf.WithHeaderSeparator(o.sepChar))
+		}
+	default:
+		lines, err := strconv.Atoi(o.header)
+		if err != nil || lines < 0 {
+			return nil, fmt.Errorf("invalid header: %s", o.header)
 		}
+		opts = append(opts, vsf.WithHeaderLines(lines), vsf.WithHeaderMode(vsf.HeaderVerbatim))
+	}
 
-		num, err := strconv.Atoi(part)
+	if o.headerMode != "" {
+		mode, err := vsf.ParseHeaderMode(o.headerMode)
 		if err != nil {
-			return nil, fmt.Errorf("invalid line number: %s", part)
+			return nil, err
 		}
+		opts = append(opts, vsf.WithHeaderMode(mode))
+	}
 
-		lineNumbers = append(lineNumbers, num)
+	style, err := vsf.LookupStyle(o.headerStyle)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, vsf.WithHeaderStyle(style))
+
+	if o.align != "" {
+		for column, name := range strings.Split(o.align, ",") {
+			align, err := vsf.ParseAlign(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, vsf.WithAlign(column, align))
+		}
 	}
 
-	return lineNumbers, nil
+	for _, expr := range o.where {
+		e, err := vsf.ParseExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, vsf.WithWhere(e))
+	}
+	if o.sort != "" {
+		keys, err := vsf.ParseSortKeys(o.sort)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, vsf.WithSort(keys...))
+	}
+
+	if o.group != "" {
+		column, err := vsf.ParseColumnRef(o.group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group: %w", err)
+		}
+		style, err := vsf.ParseGroupStyle(o.groupStyle)
+		if err != nil {
+			return nil, err
+		}
+		group := vsf.Grouping{Column: column, Style: style, SepChar: o.sepChar}
+		if o.subtotal != "" {
+			if group.Subtotals, err = vsf.ParseAggregates(o.subtotal); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, vsf.WithGroup(group))
+	}
+	if o.footer != "" {
+		aggs, err := vsf.ParseAggregates(o.footer)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, vsf.WithFooter(o.sepChar, aggs...))
+	}
+	if o.squash != "" {
+		columns, err := parseColumns(o.squash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid squash: %w", err)
+		}
+		opts = append(opts, vsf.WithSquash(o.ditto, columns...))
+	}
+	if o.hiddenKey || o.keyColumns != "" {
+		var columns []vsf.ColumnRef
+		if o.keyColumns != "" {
+			if columns, err = parseColumns(o.keyColumns); err != nil {
+				return nil, fmt.Errorf("invalid key columns: %w", err)
+			}
+		}
+		opts = append(opts, vsf.WithHiddenKey(columns...))
+	}
+	if o.transpose {
+		opts = append(opts, vsf.WithTranspose())
+	}
+	if o.number >= 0 {
+		opts = append(opts, vsf.WithNumbering(o.number))
+	}
+
+	return vsf.New(opts...)
+}
+
+// fileMode reports whether files are checked or rewritten instead of
+// printed, with -w, -l or -diff.
+func (o options) fileMode() bool {
+	return o.write || o.list || o.diff
+}
+
+// parseColumns parses comma-separated column numbers (1-based) or header
+// names.
+func parseColumns(spec string) ([]vsf.ColumnRef, error) {
+	var columns []vsf.ColumnRef
+	for _, part := range strings.Split(spec, ",") {
+		column, err := vsf.ParseColumnRef(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// listFlag collects the values of a repeatable flag.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
 
 func showUsage() {
 	fmt.Fprintf(os.Stderr, "vsf version: %s\n", VERSION)
-	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] [FILE...]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\nOptions:\n")
 	flag.PrintDefaults()
 	fmt.Fprintf(os.Stderr, "\nDescription:\n")
 	fmt.Fprintf(os.Stderr, "  This program formats input text by aligning columns based on a specified delimiter.\n")
-	fmt.Fprintf(os.Stderr, "  Input is read from stdin. Choose between basic formatting, adding separators, or\n")
-	fmt.Fprintf(os.Stderr, "  skipping certain lines from width calculations.\n")
+	fmt.Fprintf(os.Stderr, "  Input is read from the file arguments, or stdin without any. Options combine freely:\n")
+	fmt.Fprintf(os.Stderr, "  lines can be skipped from width calculations, headers kept and separators added in\n")
+	fmt.Fprintf(os.Stderr, "  the same run. With -l or -diff the exit status is 1 when a file isn't formatted,\n")
+	fmt.Fprintf(os.Stderr, "  and 2 on errors.\n")
 	fmt.Fprintf(os.Stderr, "\nExamples:\n")
 	fmt.Fprintf(os.Stderr, "  Basic formatting (most common):\n")
 	fmt.Fprintf(os.Stderr, "    echo -e \"name:john\\nage:30\\ncity:new york\" | %s\n", os.Args[0])
@@ -139,9 +440,84 @@ func showUsage() {
 	fmt.Fprintf(os.Stderr, "      ----:----\n")
 	fmt.Fprintf(os.Stderr, "      age  : 30\n")
 	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Several separators: below the header, above the totals and between groups:\n")
+	fmt.Fprintf(os.Stderr, "    cat report.txt | %s -sep 0 -sep 'before:-1:─' -sep '/^--/:·'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Skip a banner and a \"N rows returned\" footer:\n")
+	fmt.Fprintf(os.Stderr, "    psql -c '...' | %s -d '|' -skip 0-1,-1\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Pass through banners and comments wherever they appear:\n")
+	fmt.Fprintf(os.Stderr, "    some-command | %s -skip-match '^(WARN|INFO) ' -comment '#'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Title lines without the delimiter as framed sections:\n")
+	fmt.Fprintf(os.Stderr, "    cat report.txt | %s -undelimited section -section framed -sep-char '─'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Align Go assignments from an editor, keeping indentation and comments:\n")
+	fmt.Fprintf(os.Stderr, "    :'<,'>!%s -code -d ':='\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Keep lookup tables formatted in place, and check them in CI:\n")
+	fmt.Fprintf(os.Stderr, "    %s -code -d '=' -w tables/*.conf\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    %s -code -d '=' -diff tables/*.conf\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Only align the regions marked in a file, with their own options:\n")
+	fmt.Fprintf(os.Stderr, "    # vsf:align-begin d='=' align=l,r\n")
+	fmt.Fprintf(os.Stderr, "    ...\n")
+	fmt.Fprintf(os.Stderr, "    # vsf:align-end\n")
+	fmt.Fprintf(os.Stderr, "    %s -code -directives -w main.tf\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Align each section of an INI file on its own:\n")
+	fmt.Fprintf(os.Stderr, "    cat config.ini | %s -d '=' -o '=' -block-start '^\\['\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  CSV with headers:\n")
 	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -sep-after 0 | fzf --header-lines 2\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Detect the delimiter:\n")
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d auto -v\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Keep a header line, aligned with the body, and repeat it every 20 lines:\n")
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -header 1 -header-mode aligned -page 20\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Right align the second column:\n")
+	fmt.Fprintf(os.Stderr, "    du -s * | %s -input whitespace -align l,r\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Keep the slow failing requests, header names or $N refer to columns:\n")
+	fmt.Fprintf(os.Stderr, "    cat requests.csv | %s -d ',' -header 1 -where 'status != \"ok\" && latency > 200'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    cat access.log | %s -input whitespace -where '$1 =~ \"^10\\.\" || !$4'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Total the cost and average the time:\n")
+	fmt.Fprintf(os.Stderr, "    cat costs.csv | %s -d ',' -header 1 -footer sum:cost,avg:3,count:1\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Per service report with subtotals and a grand total:\n")
+	fmt.Fprintf(os.Stderr, "    cat costs.csv | %s -d ',' -header 1 -group service -subtotal sum:cost -footer sum:cost\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Sort by branch and squash repeated branches, picking the full line with fzf:\n")
+	fmt.Fprintf(os.Stderr, "    cat commits.txt | %s -sort 1,2 -squash 1 -hidden-key | fzf --delimiter '\\x1f' --with-nth 2.. | cut -d $'\\x1f' -f 1\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Pick a branch by its aligned line, getting back its exact name:\n")
+	fmt.Fprintf(os.Stderr, "    git for-each-ref --format='%%(refname:short):%%(subject)' refs/heads | %s -key-columns 1 | fzf $(%s -key-columns 1 -fzf-opts) | cut -d $'\\x1f' -f 1\n", os.Args[0], os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Sort by size, largest first, then by name, keeping the header on top:\n")
+	fmt.Fprintf(os.Stderr, "    du -sh * | %s -input whitespace -sort 1:h:desc,2\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -header 1 -sort age:n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Detect the header row and style it:\n")
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -header auto -header-style bold\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Other input formats:\n")
+	fmt.Fprintf(os.Stderr, "    docker ps | %s -input fixed\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    cat data.json | %s -input json -sep-after 0\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    cat log.txt | %s -input regex -d '\\s*[=>]\\s*'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Number the rows from 1, instead of piping through nl:\n")
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -header 1 -header-mode aligned -number 1\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Wide tables, one record per block or one column per line:\n")
+	fmt.Fprintf(os.Stderr, "    cat wide.csv | %s -d ',' -header 1 -output expanded\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    cat wide.csv | %s -d ',' -transpose\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Markdown table:\n")
+	fmt.Fprintf(os.Stderr, "    cat data.csv | %s -d ',' -output markdown\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Custom separators:\n")
 	fmt.Fprintf(os.Stderr, "    echo \"a:b:c\" | %s -o ' | ' -sep-after 0 -sep-char '='\n", os.Args[0])
 }
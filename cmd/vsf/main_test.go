@@ -2,62 +2,151 @@ package main
 
 import "testing"
 
-// Test helper functions
-func TestParseLineNumbers(t *testing.T) {
+func TestOptionsFormatter(t *testing.T) {
+	base := options{
+		delimiter:       ":",
+		outputDelimiter: "|",
+		sepAfter:        -1,
+		number:          -1,
+		sepChar:         "=",
+		headerStyle:     "none",
+		input:           "delim",
+		output:          "text",
+		undelimited:     "passthrough",
+		section:         "center",
+	}
+	const input = "BANNER\nname:age\njohn:30\namy:25"
+
 	tests := []struct {
 		name    string
-		input   string
-		want    []int
+		modify  func(o *options)
+		want    string
 		wantErr bool
 	}{
 		{
-			name:    "Valid numbers",
-			input:   "1,3,5",
-			want:    []int{1, 3, 5},
-			wantErr: false,
+			name:   "Separator and skip combine",
+			modify: func(o *options) { o.sepAfter = 1; o.skipLines = "0" },
+			want:   "BANNER\nname | age\n=====|====\njohn | 30\namy  | 25",
+		},
+		{
+			name:   "Default delimiter",
+			modify: func(o *options) { o.delimiter = ""; o.skipLines = "0" },
+			want:   "BANNER\nname | age\njohn | 30\namy  | 25",
+		},
+		{
+			name:   "Header lines and skip combine",
+			modify: func(o *options) { o.header = "1"; o.skipLines = "0" },
+			want:   "BANNER\nname:age\njohn | 30\namy  | 25",
+		},
+		{
+			name:   "Auto header adds a separator",
+			modify: func(o *options) { o.header = "auto"; o.skipLines = "0" },
+			want:   "BANNER\nname | age\n=====|====\njohn | 30\namy  | 25",
+		},
+		{
+			name:   "Auto header keeps an explicit separator",
+			modify: func(o *options) { o.header = "auto"; o.skipLines = "0"; o.sepAfter = 2 },
+			want:   "BANNER\nname | age\njohn | 30\n=====|===\namy  | 25",
+		},
+		{
+			name:   "Header mode overrides the default",
+			modify: func(o *options) { o.header = "1"; o.skipLines = "0"; o.headerMode = "aligned" },
+			want:   "BANNER\nname | age\njohn | 30\namy  | 25",
+		},
+		{
+			name:   "Alignment and skip combine",
+			modify: func(o *options) { o.skipLines = "0"; o.align = "r,r" },
+			want:   "BANNER\nname | age\njohn |  30\n amy |  25",
+		},
+		{
+			name:   "Numbering keeps alignments on the input columns",
+			modify: func(o *options) { o.skipLines = "0"; o.align = "l,r"; o.number = 1 },
+			want:   "BANNER\n1 | name | age\n2 | john |  30\n3 | amy  |  25",
+		},
+		{
+			name:   "Separator rules and skip combine",
+			modify: func(o *options) { o.skipLines = "0"; o.seps = listFlag{"1", "before:-1:-"} },
+			want:   "BANNER\nname | age\n=====|====\njohn | 30\n-----|---\namy  | 25",
+		},
+		{
+			name:   "Skip pattern and comment",
+			modify: func(o *options) { o.skipMatch = listFlag{"^BAN"}; o.comment = "#" },
+			want:   "BANNER\nname | age\njohn | 30\namy  | 25",
 		},
 		{
-			name:    "Single number",
-			input:   "2",
-			want:    []int{2},
-			wantErr: false,
+			name:    "Invalid skip pattern",
+			modify:  func(o *options) { o.skipMatch = listFlag{"("} },
+			wantErr: true,
+		},
+		{
+			name:    "Invalid separator",
+			modify:  func(o *options) { o.seps = listFlag{"x"} },
+			wantErr: true,
+		},
+		{
+			name:   "Undelimited lines aligned",
+			modify: func(o *options) { o.undelimited = "aligned" },
+			want:   "BANNER\nname   | age\njohn   | 30\namy    | 25",
+		},
+		{
+			name: "Framed section",
+			modify: func(o *options) {
+				o.undelimited = "section"
+				o.section = "framed"
+				o.sepChar = "-"
+			},
+			want: "- BANNER -\nname | age\njohn | 30\namy  | 25",
+		},
+		{
+			name:    "Invalid section style",
+			modify:  func(o *options) { o.section = "boxed" },
+			wantErr: true,
+		},
+		{
+			name:   "Code keeps the input delimiter",
+			modify: func(o *options) { o.code = true; o.outputDelimiter = "" },
+			want:   "BANNER\nname : age\njohn : 30\namy  : 25",
+		},
+		{
+			name:    "Invalid alignment",
+			modify:  func(o *options) { o.align = "x" },
+			wantErr: true,
 		},
 		{
-			name:    "Empty string",
-			input:   "",
-			want:    nil,
-			wantErr: false,
+			name:    "Invalid header",
+			modify:  func(o *options) { o.header = "x" },
+			wantErr: true,
 		},
 		{
-			name:    "With spaces",
-			input:   " 1 , 3 , 5 ",
-			want:    []int{1, 3, 5},
-			wantErr: false,
+			name:   "Skip ranges and negative lines",
+			modify: func(o *options) { o.skipLines = "0-1,-1" },
+			want:   "BANNER\nname:age\njohn | 30\namy:25",
 		},
 		{
-			name:    "Invalid number",
-			input:   "1,abc,3",
-			want:    nil,
+			name:    "Invalid skip lines",
+			modify:  func(o *options) { o.skipLines = "a" },
 			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseLineNumbers(tt.input)
+			o := base
+			tt.modify(&o)
+			formatter, err := o.formatter()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseLineNumbers() error = %v, wantErr %v", err, tt.wantErr)
-				return
+				t.Fatalf("formatter() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if len(got) != len(tt.want) {
-				t.Errorf("parseLineNumbers() = %v, want %v", got, tt.want)
+			if tt.wantErr {
 				return
 			}
-			for i, v := range got {
-				if v != tt.want[i] {
-					t.Errorf("parseLineNumbers() = %v, want %v", got, tt.want)
-					break
-				}
+
+			got, err := formatter.Format(input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
 			}
 		})
 	}
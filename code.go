@@ -0,0 +1,149 @@
+package vsf
+
+import "strings"
+
+// DefaultCodeComments are the trailing comment markers recognized in code
+// mode when none are given.
+var DefaultCodeComments = []string{"//", "#"}
+
+// CodeParser splits source and config lines on a literal delimiter like
+// DelimiterParser, but keeps what code needs intact: lines are split
+// only at their first delimiter outside quotes, into a key and a value
+// like "http://host:80", the leading whitespace of each line goes to
+// Row.Indent and a trailing comment, starting at one of Comments outside
+// quotes, goes to Row.Comment without being split.
+//
+// Example:
+//
+//	t, _ := CodeParser{Delimiter: "="}.Parse("\tname = 'x' # a=b")
+//	// t.Rows[0].Indent  = "\t"
+//	// t.Rows[0].Cells   = ["name", "'x'"]
+//	// t.Rows[0].Comment = " # a=b"
+type CodeParser struct {
+	Delimiter string
+	// Comments are the markers starting a trailing comment. Nil uses
+	// DefaultCodeComments.
+	Comments []string
+}
+
+// Parse implements Parser. Unlike the other parsers it only drops blank
+// leading and trailing lines, keeping the indentation of the first one.
+func (p CodeParser) Parse(input string) (*Table, error) {
+	comments := p.Comments
+	if comments == nil {
+		comments = DefaultCodeComments
+	}
+
+	lines := strings.Split(input, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	t := &Table{Rows: make([]Row, len(lines))}
+	for i, line := range lines {
+		code := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(code)]
+		code, comment := splitComment(code, comments)
+		cells, delimited := cutDelimiter(code, p.Delimiter)
+		t.Rows[i] = Row{
+			Kind:        RowData,
+			Cells:       cells,
+			Raw:         line,
+			Line:        i,
+			Record:      i,
+			Indent:      indent,
+			Comment:     comment,
+			Undelimited: !delimited,
+		}
+	}
+	return t, nil
+}
+
+// cutDelimiter splits line at its first delimiter outside quotes into a
+// trimmed key and value. Without a delimiter, or with an empty value
+// like a YAML key opening a block, the key is the only cell and line
+// counts as undelimited, so it keeps its layout.
+func cutDelimiter(line, delimiter string) (cells []string, delimited bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, false
+	}
+
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		char := line[i]
+		if char == '"' || char == '\'' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if !inQuotes && strings.HasPrefix(line[i:], delimiter) {
+			key := strings.TrimSpace(line[:i])
+			if value := strings.TrimSpace(line[i+len(delimiter):]); value != "" {
+				return []string{key, value}, true
+			}
+			return []string{key}, false
+		}
+	}
+	return []string{line}, false
+}
+
+// splitComment cuts line before the first comment marker found outside
+// quotes, at the start of the line or after a blank. The comment keeps
+// the blanks in front of it.
+func splitComment(line string, markers []string) (code, comment string) {
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		char := line[i]
+		if char == '"' || char == '\'' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if inQuotes || (i > 0 && line[i-1] != ' ' && line[i-1] != '\t') {
+			continue
+		}
+		for _, marker := range markers {
+			if strings.HasPrefix(line[i:], marker) {
+				code = strings.TrimRight(line[:i], " \t")
+				return code, line[len(code):]
+			}
+		}
+	}
+	return line, ""
+}
+
+// Runs splits t into consecutive runs of rows, so that each can be laid
+// out on its own widths: aligned rows sharing the same indentation, with
+// the separators drawn for them, or rows that aren't aligned.
+func (t *Table) Runs() []*Table {
+	var (
+		runs []*Table
+		key  string
+	)
+	for i, row := range t.Rows {
+		next := key
+		switch {
+		case row.aligned():
+			next = "aligned:" + row.Indent
+		case row.Kind != RowSeparator:
+			next = "other"
+		}
+
+		if len(runs) == 0 || next != key {
+			runs = append(runs, &Table{
+				HeaderMode:   t.HeaderMode,
+				SectionStyle: t.SectionStyle,
+				SectionChar:  t.SectionChar,
+			})
+			key = next
+		}
+		run := runs[len(runs)-1]
+		run.Rows = append(run.Rows, t.Rows[i])
+	}
+	return runs
+}
@@ -0,0 +1,96 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodeParser(t *testing.T) {
+	table, err := CodeParser{Delimiter: "="}.Parse("\n\tname = 'a # b' # x = y\n  # only a comment\nurl=http://host\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Row{
+		{Cells: []string{"name", "'a # b'"}, Raw: "\tname = 'a # b' # x = y", Line: 0, Indent: "\t", Comment: " # x = y"},
+		{Raw: "  # only a comment", Line: 1, Record: 1, Indent: "  ", Comment: "# only a comment", Undelimited: true},
+		{Cells: []string{"url", "http://host"}, Raw: "url=http://host", Line: 2, Record: 2},
+	}
+	if !reflect.DeepEqual(table.Rows, want) {
+		t.Errorf("Rows = %+v, want %+v", table.Rows, want)
+	}
+}
+
+func TestFormatterCode(t *testing.T) {
+	const input = "" +
+		"func main() {\n" +
+		"\ta := 1 // one := 1\n" +
+		"\tbbbb := 2\n" +
+		"\tif x {\n" +
+		"\t\tcc := 3\n" +
+		"\t\td := 4\n" +
+		"\t}\n" +
+		"\teeeee := 5\n" +
+		"}"
+	const want = "" +
+		"func main() {\n" +
+		"\ta    := 1 // one := 1\n" +
+		"\tbbbb := 2\n" +
+		"\tif x {\n" +
+		"\t\tcc := 3\n" +
+		"\t\td  := 4\n" +
+		"\t}\n" +
+		"\teeeee := 5\n" +
+		"}"
+
+	f, err := New(WithDelimiter(":="), WithCode())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	got, err := f.Format(input)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	if _, err := New(WithDelimiter(AutoDelimiter), WithCode()); err == nil {
+		t.Error("New() expected error for code mode with a detected delimiter")
+	}
+}
+
+func TestFormatterCodeFirstDelimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		input     string
+		want      string
+	}{
+		{
+			name:      "YAML with URLs",
+			delimiter: ":",
+			input:     "server:\n  url: http://example.com:8080\n  timeout: 30s\n  proxy: 'a:b'",
+			want:      "server:\n  url     : http://example.com:8080\n  timeout : 30s\n  proxy   : 'a:b'",
+		},
+		{
+			name:      "Go short variable declarations",
+			delimiter: ":=",
+			input:     "\tn := 1\n\tf := func() { x := 2 }\n\tname := \"a:=b\"",
+			want:      "\tn    := 1\n\tf    := func() { x := 2 }\n\tname := \"a:=b\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := New(WithDelimiter(tt.delimiter), WithCode())
+			got, err := f.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package vsf
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ColumnRef refers to a column by its 0-based index or, when Name is
+// set, by its title in the first header row.
+type ColumnRef struct {
+	Index int
+	Name  string
+}
+
+// ParseColumnRef parses a 1-based column number or a header name.
+//
+// Example:
+//
+//	ParseColumnRef("2")     // ColumnRef{Index: 1}
+//	ParseColumnRef("name")  // ColumnRef{Name: "name"}
+func ParseColumnRef(s string) (ColumnRef, error) {
+	if s == "" {
+		return ColumnRef{}, fmt.Errorf("empty column")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return ColumnRef{Name: s}, nil
+	}
+	if n < 1 {
+		return ColumnRef{}, fmt.Errorf("invalid column: %s, columns start at 1", s)
+	}
+	return ColumnRef{Index: n - 1}, nil
+}
+
+// String returns the form accepted by ParseColumnRef.
+func (r ColumnRef) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strconv.Itoa(r.Index + 1)
+}
+
+// resolve returns the 0-based index of the column in t. Indexes past
+// the widest row are unknown, unless t has no rows to align at all.
+func (r ColumnRef) resolve(t *Table) (int, error) {
+	if r.Name == "" {
+		if n := t.columns(); n > 0 && r.Index >= n {
+			return 0, fmt.Errorf("unknown column: %s", r)
+		}
+		return r.Index, nil
+	}
+	for _, row := range t.Rows {
+		if row.Kind != RowHeader {
+			continue
+		}
+		for i, cell := range row.Cells {
+			if cell == r.Name {
+				return i, nil
+			}
+		}
+		break
+	}
+	return 0, fmt.Errorf("unknown column: %s", r.Name)
+}
+
+// columns returns the number of cells of the widest aligned row.
+func (t *Table) columns() int {
+	n := 0
+	for _, row := range t.Rows {
+		if row.aligned() {
+			n = max(n, len(row.Cells))
+		}
+	}
+	return n
+}
+
+// cellAt returns the cell of row in column i, or "" when the row is short.
+func cellAt(row Row, i int) string {
+	if i < len(row.Cells) {
+		return row.Cells[i]
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package vsf
+
+import "testing"
+
+func TestColumnRefResolve(t *testing.T) {
+	table, _ := ParseTable("name:age\njohn:30\n# note", ":")
+	table.PassUndelimited()
+	table.Rows[0].Kind = RowHeader
+
+	tests := []struct {
+		ref     ColumnRef
+		want    int
+		wantErr bool
+	}{
+		{ref: ColumnRef{Index: 1}, want: 1},
+		{ref: ColumnRef{Name: "age"}, want: 1},
+		{ref: ColumnRef{Index: 2}, wantErr: true},
+		{ref: ColumnRef{Name: "city"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref.String(), func(t *testing.T) {
+			got, err := tt.ref.resolve(table)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("resolve() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package vsf
+
+import "regexp"
+
+// Config holds every formatting setting of a Formatter. Options write to
+// it; all settings compose, so lines can be skipped, headers kept and
+// separators added in the same run.
+type Config struct {
+	// Delimiter splits lines into cells. Set it to AutoDelimiter to detect
+	// it from the input. Empty uses "," with the csv input and ":"
+	// otherwise.
+	Delimiter string
+	// OutputDelimiter is written between cells. Empty uses Delimiter, or
+	// with AutoDelimiter the delimiter detected in the input.
+	OutputDelimiter string
+	// Input is the name of the parser. Empty uses DefaultParser.
+	Input string
+	// Parser, when set, is used instead of the parser named by Input.
+	Parser Parser
+	// Output is the name of the renderer. Empty uses DefaultRenderer.
+	Output string
+	// Renderer, when set, is used instead of the renderer named by Output.
+	Renderer Renderer
+
+	// Code keeps the indentation and trailing comments of source lines and
+	// only aligns consecutive lines sharing the same indentation.
+	Code bool
+	// CodeComments are the trailing comment markers of Code. Nil uses
+	// DefaultCodeComments.
+	CodeComments []string
+
+	// Directives only aligns the regions of the input marked by directive
+	// comments. See ParseDirective.
+	Directives bool
+
+	// Blocks splits the input at blank lines into tables aligned on their
+	// own widths. Line numbers then count from the start of each block.
+	Blocks bool
+	// BlockStart, when set with Blocks, also starts a block at every line
+	// matching it.
+	BlockStart *regexp.Regexp
+
+	// SkipLines are the source lines written as-is and left out of the
+	// column widths.
+	SkipLines LineSpec
+	// SkipMatch are patterns; source lines matching any of them are
+	// written as-is and left out of the column widths.
+	SkipMatch []*regexp.Regexp
+	// Undelimited controls how lines without the delimiter are laid out.
+	Undelimited UndelimitedMode
+	// SectionMatch are patterns; source lines matching any of them become
+	// section rows spanning the table.
+	SectionMatch []*regexp.Regexp
+	// SectionStyle controls how section rows are laid out.
+	SectionStyle SectionStyle
+	// SectionChar frames section rows with SectionFramed.
+	SectionChar string
+	// Separators are separator lines to add.
+	Separators []Separator
+
+	// HeaderLines is the number of leading data rows kept as header.
+	HeaderLines int
+	// HeaderAuto detects a single header row when HeaderLines is 0.
+	HeaderAuto bool
+	// HeaderMode controls how header rows are laid out.
+	HeaderMode HeaderMode
+	// HeaderSep, when set, draws a separator below the header.
+	HeaderSep string
+	// HeaderStyle decorates the header rows.
+	HeaderStyle Style
+	// PageSize repeats the header every PageSize data rows.
+	PageSize int
+
+	// Where keeps only the data rows matching these expressions.
+	Where []*Expr
+	// Sort orders the data rows by these keys.
+	Sort []SortKey
+
+	// Group, when set, gathers the data rows by a key column.
+	Group *Grouping
+	// Footer are the aggregates written in footer rows below the data.
+	Footer []Aggregate
+	// FooterSep, when set, draws a separator above the footer.
+	FooterSep string
+
+	// Squash blanks out the cells of these columns equal to the cell
+	// above, writing Ditto instead.
+	Squash []ColumnRef
+	// Ditto replaces squashed cells.
+	Ditto string
+	// KeyDelimiter, when set, writes hidden key fields before each row,
+	// each ended by it: the cells of KeyColumns, or the source line.
+	KeyDelimiter string
+	KeyColumns   []ColumnRef
+	// Transpose swaps rows and columns, see Table.Transpose.
+	Transpose bool
+	// Numbering prepends a right aligned index column numbering the data
+	// rows of the input from NumberFrom, see Table.Number. Align,
+	// MinWidth and MaxWidth still refer to the columns of the input.
+	Numbering  bool
+	NumberFrom int
+
+	// Align sets the alignment of columns by 0-based index.
+	Align map[int]Align
+	// MinWidth pads columns, by 0-based index, to at least this width.
+	MinWidth map[int]int
+	// MaxWidth truncates the cells of columns, by 0-based index, to at
+	// most this width.
+	MaxWidth map[int]int
+
+	// Logf, when set, receives reports of detected settings.
+	Logf func(format string, args ...any)
+}
@@ -0,0 +1,115 @@
+package vsf
+
+import "strings"
+
+// AutoDelimiter is the delimiter value that asks for the delimiter to be
+// detected from the input.
+const AutoDelimiter = "auto"
+
+// detectSampleLines is the number of non-blank lines DetectDelimiter reads.
+const detectSampleLines = 50
+
+// delimiterCandidates are tried by DetectDelimiter, in order of
+// preference when two of them split the sample equally well.
+var delimiterCandidates = []string{",", "\t", ";", "|", ":"}
+
+// Detection is the delimiter chosen by DetectDelimiter.
+type Detection struct {
+	// Delimiter is the literal delimiter. It is empty when Whitespace is set.
+	Delimiter string
+	// Whitespace reports that columns are separated by runs of blanks.
+	Whitespace bool
+	// Columns is the number of columns most sample lines split into.
+	Columns int
+	// Consistency is the fraction of sample lines with that many columns.
+	Consistency float64
+}
+
+// String returns a printable form of the delimiter.
+func (d Detection) String() string {
+	switch {
+	case d.Whitespace:
+		return "whitespace"
+	case d.Delimiter == "\t":
+		return `"\t"`
+	}
+	return `"` + d.Delimiter + `"`
+}
+
+// Parser returns a parser splitting on the detected delimiter.
+func (d Detection) Parser() Parser {
+	if d.Whitespace {
+		return WhitespaceParser{}
+	}
+	return DelimiterParser{Delimiter: d.Delimiter}
+}
+
+// DetectDelimiter sniffs the delimiter from a sample of the input. Each
+// candidate (",", "\t", ";", "|", ":" and runs of whitespace) is scored by
+// how consistently it splits the sample lines into the same number of
+// columns, ignoring delimiters inside quotes. It returns false when no
+// candidate splits the sample into at least two columns.
+//
+// Example:
+//
+//	d, _ := DetectDelimiter("name,age\njohn,30\namy,25")
+//	// d.Delimiter = ",", d.Columns = 2
+func DetectDelimiter(input string) (Detection, bool) {
+	var sample []string
+	for _, line := range strings.Split(input, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sample = append(sample, line)
+		if len(sample) == detectSampleLines {
+			break
+		}
+	}
+	if len(sample) == 0 {
+		return Detection{}, false
+	}
+
+	var (
+		best  Detection
+		found bool
+	)
+	consider := func(d Detection, counts []int) {
+		d.Columns, d.Consistency = modalCount(counts)
+		if d.Columns < 2 {
+			return
+		}
+		// Strictly better only, so earlier candidates win ties
+		if !found || d.Consistency > best.Consistency {
+			best, found = d, true
+		}
+	}
+
+	counts := make([]int, len(sample))
+	for _, delimiter := range delimiterCandidates {
+		for i, line := range sample {
+			counts[i] = len(ParseLine(line, delimiter))
+		}
+		consider(Detection{Delimiter: delimiter}, counts)
+	}
+
+	for i, line := range sample {
+		counts[i] = len(splitWhitespace(strings.TrimSpace(line)))
+	}
+	consider(Detection{Whitespace: true}, counts)
+
+	return best, found
+}
+
+// modalCount returns the most frequent value of counts, preferring the
+// larger value on ties, and the fraction of counts equal to it.
+func modalCount(counts []int) (int, float64) {
+	freq := make(map[int]int)
+	mode := 0
+	for _, count := range counts {
+		freq[count]++
+		if freq[count] > freq[mode] || (freq[count] == freq[mode] && count > mode) {
+			mode = count
+		}
+	}
+	return mode, float64(freq[mode]) / float64(len(counts))
+}
@@ -0,0 +1,78 @@
+package vsf
+
+import "testing"
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       string
+		whitespace bool
+		columns    int
+		wantOK     bool
+	}{
+		{
+			name:    "Comma",
+			input:   "name,age\njohn,30\namy,25",
+			want:    ",",
+			columns: 2,
+			wantOK:  true,
+		},
+		{
+			name:    "Tab",
+			input:   "name\tage\tcity\njohn\t30\tnew york",
+			want:    "\t",
+			columns: 3,
+			wantOK:  true,
+		},
+		{
+			name:    "Quoted delimiters are ignored",
+			input:   "\"a;b\";c\n'x;y';z\n1;2",
+			want:    ";",
+			columns: 2,
+			wantOK:  true,
+		},
+		{
+			name:    "Consistency beats column count",
+			input:   "10:00,start\n11:30,stop\n12:45,done",
+			want:    ",",
+			columns: 2,
+			wantOK:  true,
+		},
+		{
+			name:    "Pipe over colon in times",
+			input:   "when|what\n10:00|start\n11:30:15|stop",
+			want:    "|",
+			columns: 2,
+			wantOK:  true,
+		},
+		{
+			name:       "Whitespace runs",
+			input:      "PID   CMD\n1     init\n234   bash",
+			whitespace: true,
+			columns:    2,
+			wantOK:     true,
+		},
+		{
+			name:   "Nothing to split",
+			input:  "hello\nworld",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectDelimiter(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("DetectDelimiter() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Delimiter != tt.want || got.Whitespace != tt.whitespace || got.Columns != tt.columns {
+				t.Errorf("DetectDelimiter() = %+v, want delimiter %q, whitespace %v, columns %d",
+					got, tt.want, tt.whitespace, tt.columns)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package vsf
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Directives are markers, written inside a comment of any syntax, that
+// select the regions of a file to align:
+//
+//	# vsf:align-begin d='=' header=1
+//	...
+//	# vsf:align-end
+//
+// Lines between "vsf:off" and "vsf:on" are never changed.
+const (
+	DirectiveBegin = "vsf:align-begin"
+	DirectiveEnd   = "vsf:align-end"
+	DirectiveOff   = "vsf:off"
+	DirectiveOn    = "vsf:on"
+)
+
+// directiveRe finds a directive and the text following it.
+var directiveRe = regexp.MustCompile(`vsf:(align-begin|align-end|off|on)\b(.*)`)
+
+// directiveOptions maps the keys of align-begin options to the option
+// they set.
+var directiveOptions = map[string]func(value string) (Option, error){
+	"d": func(value string) (Option, error) {
+		return WithDelimiter(value), nil
+	},
+	"o": func(value string) (Option, error) {
+		return WithOutputDelimiter(value), nil
+	},
+	"header": func(value string) (Option, error) {
+		if value == "auto" {
+			return WithHeaderAuto(), nil
+		}
+		lines, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header: %s", value)
+		}
+		return WithHeaderLines(lines), nil
+	},
+	"skip": func(value string) (Option, error) {
+		spec, err := ParseLineSpec(value)
+		if err != nil {
+			return nil, err
+		}
+		return WithSkip(spec), nil
+	},
+	"align": func(value string) (Option, error) {
+		var opts []Option
+		for col, name := range strings.Split(value, ",") {
+			align, err := ParseAlign(name)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithAlign(col, align))
+		}
+		return func(c *Config) error {
+			for _, opt := range opts {
+				if err := opt(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	},
+	"code": func(value string) (Option, error) {
+		code, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code: %s", value)
+		}
+		return func(c *Config) error {
+			c.Code = code
+			return nil
+		}, nil
+	},
+}
+
+// ParseDirective finds a directive in line. It returns the directive,
+// one of the Directive constants, and for DirectiveBegin the options
+// set by its key=value arguments: d, o, header, skip, align and code.
+// Values may be quoted; arguments without "=", like the end of a
+// comment, are ignored. It returns "" when line has no directive.
+//
+// Example:
+//
+//	ParseDirective(`<!-- vsf:align-begin d="|" align=l,r -->`)
+//	// DirectiveBegin, options setting the delimiter and alignment
+func ParseDirective(line string) (string, []Option, error) {
+	m := directiveRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", nil, nil
+	}
+	directive := "vsf:" + m[1]
+	if directive != DirectiveBegin {
+		return directive, nil, nil
+	}
+
+	var opts []Option
+	for _, arg := range splitWhitespace(m[2]) {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			continue
+		}
+		option, known := directiveOptions[key]
+		if !known {
+			return "", nil, fmt.Errorf("unknown directive option: %s", key)
+		}
+		opt, err := option(unquote(value))
+		if err != nil {
+			return "", nil, err
+		}
+		opts = append(opts, opt)
+	}
+	return directive, opts, nil
+}
+
+// unquote removes a pair of single or double quotes around s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// renderDirectives writes input as-is except for the regions marked by
+// directives, each aligned on its own with the options of its
+// align-begin line. Without any align-begin, every stretch of lines
+// outside vsf:off and vsf:on is aligned instead.
+func (f *Formatter) renderDirectives(w io.Writer, input string) error {
+	lines := strings.SplitAfter(input, "\n")
+	regions := strings.Contains(input, DirectiveBegin)
+
+	var (
+		region   []string
+		start    int
+		opts     []Option
+		off      bool
+		aligning = !regions
+	)
+	flush := func() error {
+		defer func() { region = nil }()
+		if len(region) == 0 {
+			return nil
+		}
+		out, err := f.formatRegion(region, opts)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", start+1, err)
+		}
+		_, err = io.WriteString(w, out)
+		return err
+	}
+
+	for i, line := range lines {
+		directive, args, err := ParseDirective(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if directive == "" && !off && aligning {
+			if len(region) == 0 {
+				start = i
+			}
+			region = append(region, line)
+			continue
+		}
+
+		// Any other line ends the region and is written as-is
+		if err := flush(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+
+		switch {
+		case directive == DirectiveOff:
+			off = true
+		case directive == DirectiveOn:
+			off = false
+		case off:
+			// Regions can't start or end while formatting is off
+		case directive == DirectiveBegin:
+			aligning, opts = true, args
+		case directive == DirectiveEnd:
+			aligning, opts = !regions, nil
+		}
+	}
+	return flush()
+}
+
+// formatRegion aligns the lines of a region with the formatter's
+// settings and opts. Blank lines around it are kept, and so is a missing
+// final newline.
+func (f *Formatter) formatRegion(lines []string, opts []Option) (string, error) {
+	first, last := 0, len(lines)
+	for first < last && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	for last > first && strings.TrimSpace(lines[last-1]) == "" {
+		last--
+	}
+	if first == last {
+		return strings.Join(lines, ""), nil
+	}
+
+	config := f.config
+	config.Directives = false
+	region, err := newFormatter(config, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.Join(lines[first:last], "")
+	var b strings.Builder
+	if err := region.Render(&b, text); err != nil {
+		return "", err
+	}
+	out := b.String()
+	if !strings.HasSuffix(text, "\n") {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return strings.Join(lines[:first], "") + out + strings.Join(lines[last:], ""), nil
+}
@@ -0,0 +1,100 @@
+package vsf
+
+import "testing"
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		opts    int
+		wantErr bool
+	}{
+		{line: "name:john", want: ""},
+		{line: "<!-- vsf:align-begin d='|' align=l,r -->", want: DirectiveBegin, opts: 2},
+		{line: "/* vsf:align-end */", want: DirectiveEnd},
+		{line: "# vsf:off", want: DirectiveOff},
+		{line: "-- vsf:on", want: DirectiveOn},
+		{line: "# vsf:align-begin delim=|", wantErr: true},
+		{line: "# vsf:align-begin header=x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, opts, err := ParseDirective(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDirective() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || len(opts) != tt.opts {
+				t.Errorf("ParseDirective() = %q with %d options, want %q with %d", got, len(opts), tt.want, tt.opts)
+			}
+		})
+	}
+}
+
+func TestFormatterDirectives(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "Regions",
+			input: "" +
+				"a:b\n" +
+				"ccc:d\n" +
+				"# vsf:align-begin\n" +
+				"e:f\n" +
+				"ggg:h\n" +
+				"# vsf:align-end\n" +
+				"// vsf:align-begin d=\"=\" o='='\n" +
+				"\n" +
+				"i=j\n" +
+				"kkk=l\n" +
+				"// vsf:align-end\n" +
+				"m:n",
+			want: "" +
+				"a:b\n" +
+				"ccc:d\n" +
+				"# vsf:align-begin\n" +
+				"e   | f\n" +
+				"ggg | h\n" +
+				"# vsf:align-end\n" +
+				"// vsf:align-begin d=\"=\" o='='\n" +
+				"\n" +
+				"i   = j\n" +
+				"kkk = l\n" +
+				"// vsf:align-end\n" +
+				"m:n",
+		},
+		{
+			name:  "Off outside regions",
+			input: "a:b\nccc:d\n# vsf:off\nx:y\nzzzz:w\n# vsf:on\ne:f\n",
+			want:  "a   | b\nccc | d\n# vsf:off\nx:y\nzzzz:w\n# vsf:on\ne | f",
+		},
+		{
+			name:  "Off inside a region",
+			input: "<!-- vsf:align-begin -->\na:b\n<!-- vsf:off -->\nx:y\n<!-- vsf:on -->\nccc:d\n<!-- vsf:align-end -->",
+			want:  "<!-- vsf:align-begin -->\na | b\n<!-- vsf:off -->\nx:y\n<!-- vsf:on -->\nccc | d\n<!-- vsf:align-end -->",
+		},
+	}
+
+	f, err := New(WithOutputDelimiter("|"), WithDirectives())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := f.Format("# vsf:align-begin nope=1\na:b"); err == nil {
+		t.Error("Format() expected error for an unknown directive option")
+	}
+}
@@ -0,0 +1,389 @@
+package vsf
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expr is a compiled row filter. See ParseExpr for the syntax.
+type Expr struct {
+	src     string
+	root    cond
+	columns []ColumnRef
+}
+
+// ParseExpr compiles a filter expression over the cells of a row:
+//
+//   - columns are $N (1-based), a header name like status, or ${any name}
+//   - literals are numbers and single or double quoted strings
+//   - comparisons are == != < <= > >=, numeric when both sides are
+//     numbers and on strings otherwise
+//   - =~ and !~ match a column against a quoted regular expression
+//   - conditions combine with &&, || and !, and group with parentheses
+//   - a column alone is true when its cell isn't empty
+//
+// Example:
+//
+//	e, _ := ParseExpr(`status != "ok" && latency > 200`)
+func ParseExpr(s string) (*Expr, error) {
+	tokens, err := lexExpr(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", s, err)
+	}
+
+	p := &exprParser{tokens: tokens, expr: &Expr{src: s}}
+	root, err := p.or()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", s, err)
+	}
+	p.expr.root = root
+	return p.expr, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Filter removes the data rows for which e is false, so they don't
+// affect the column widths. Other rows are kept.
+func (t *Table) Filter(e *Expr) error {
+	columns := make([]int, len(e.columns))
+	for i, ref := range e.columns {
+		column, err := ref.resolve(t)
+		if err != nil {
+			return err
+		}
+		columns[i] = column
+	}
+
+	rows := t.Rows[:0]
+	for _, row := range t.Rows {
+		if row.Kind != RowData || e.root.match(row, columns) {
+			rows = append(rows, row)
+		}
+	}
+	t.Rows = rows
+	return nil
+}
+
+// cond is a node of the expression evaluating to true or false. columns
+// are the resolved indexes of Expr.columns.
+type cond interface {
+	match(row Row, columns []int) bool
+}
+
+// operand is a node of the expression evaluating to a string.
+type operand interface {
+	value(row Row, columns []int) string
+}
+
+type (
+	orCond      struct{ left, right cond }
+	andCond     struct{ left, right cond }
+	notCond     struct{ cond cond }
+	truthyCond  struct{ operand operand }
+	compareCond struct {
+		op          string
+		left, right operand
+	}
+	matchCond struct {
+		operand operand
+		re      *regexp.Regexp
+		negate  bool
+	}
+
+	// columnOperand is the cell of the column in slot of Expr.columns.
+	columnOperand  struct{ slot int }
+	literalOperand string
+)
+
+func (c orCond) match(row Row, columns []int) bool {
+	return c.left.match(row, columns) || c.right.match(row, columns)
+}
+
+func (c andCond) match(row Row, columns []int) bool {
+	return c.left.match(row, columns) && c.right.match(row, columns)
+}
+
+func (c notCond) match(row Row, columns []int) bool {
+	return !c.cond.match(row, columns)
+}
+
+func (c truthyCond) match(row Row, columns []int) bool {
+	return c.operand.value(row, columns) != ""
+}
+
+func (c matchCond) match(row Row, columns []int) bool {
+	return c.re.MatchString(c.operand.value(row, columns)) != c.negate
+}
+
+func (c compareCond) match(row Row, columns []int) bool {
+	a, b := c.left.value(row, columns), c.right.value(row, columns)
+
+	var result int
+	x, okA := parseNumber(a)
+	y, okB := parseNumber(b)
+	if okA && okB {
+		result = cmp.Compare(x, y)
+	} else {
+		result = strings.Compare(a, b)
+	}
+
+	switch c.op {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	}
+	return result >= 0
+}
+
+func (o columnOperand) value(row Row, columns []int) string {
+	return cellAt(row, columns[o.slot])
+}
+
+func (o literalOperand) value(Row, []int) string {
+	return string(o)
+}
+
+// tokenKind is the kind of a token of an expression.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokColumn
+	tokString
+	tokNumber
+	tokOp
+)
+
+// token is a lexed piece of an expression.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// String describes the token in error messages.
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOps are the operators, longest first so "<=" wins over "<".
+var exprOps = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")"}
+
+var (
+	exprNumberRe = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?`)
+	exprNameRe   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// lexExpr splits an expression into tokens. The text of column tokens is
+// the column reference as accepted by ParseColumnRef, and the text of
+// string tokens is unquoted.
+func lexExpr(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case c == '"' || c == '\'':
+			text, n, err := lexString(rest)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, text})
+			i += n
+			continue
+		case strings.HasPrefix(rest, "${"):
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated column name")
+			}
+			tokens = append(tokens, token{tokColumn, rest[2:end]})
+			i += end + 1
+			continue
+		case c == '$':
+			digits := strings.TrimLeft(rest[1:], "0123456789")
+			n := len(rest) - len(digits)
+			if n == 1 {
+				return nil, fmt.Errorf("missing column number after $")
+			}
+			tokens = append(tokens, token{tokColumn, rest[1:n]})
+			i += n
+			continue
+		}
+
+		if m := exprNumberRe.FindString(rest); m != "" {
+			tokens = append(tokens, token{tokNumber, m})
+			i += len(m)
+			continue
+		}
+		if m := exprNameRe.FindString(rest); m != "" {
+			tokens = append(tokens, token{tokColumn, m})
+			i += len(m)
+			continue
+		}
+
+		op := ""
+		for _, candidate := range exprOps {
+			if strings.HasPrefix(rest, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("unexpected character %q", s[i])
+		}
+		tokens = append(tokens, token{tokOp, op})
+		i += len(op)
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+// lexString reads the quoted string at the start of s, returning its
+// unquoted text and its length in s. A backslash escapes the quote and
+// itself; other escapes are kept, so patterns like "\." read as written.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\') {
+				i++
+			}
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// exprParser is a recursive descent parser over the tokens of an
+// expression, from the lowest precedence (||) to the highest (!).
+type exprParser struct {
+	tokens []token
+	pos    int
+	expr   *Expr
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token when it is the operator op.
+func (p *exprParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) or() (cond, error) {
+	left, err := p.and()
+	for err == nil && p.accept("||") {
+		var right cond
+		right, err = p.and()
+		left = orCond{left, right}
+	}
+	return left, err
+}
+
+func (p *exprParser) and() (cond, error) {
+	left, err := p.unary()
+	for err == nil && p.accept("&&") {
+		var right cond
+		right, err = p.unary()
+		left = andCond{left, right}
+	}
+	return left, err
+}
+
+func (p *exprParser) unary() (cond, error) {
+	if p.accept("!") {
+		c, err := p.unary()
+		return notCond{c}, err
+	}
+	if p.accept("(") {
+		c, err := p.or()
+		if err == nil && !p.accept(")") {
+			err = fmt.Errorf("expected \")\", found %s", p.peek())
+		}
+		return c, err
+	}
+	return p.comparison()
+}
+
+func (p *exprParser) comparison() (cond, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokOp {
+		return truthyCond{left}, nil
+	}
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.operand()
+		return compareCond{op: t.text, left: left, right: right}, err
+	case "=~", "!~":
+		p.next()
+		pattern := p.next()
+		if pattern.kind != tokString {
+			return nil, fmt.Errorf("%s needs a quoted pattern, found %s", t.text, pattern)
+		}
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, err
+		}
+		return matchCond{operand: left, re: re, negate: t.text == "!~"}, nil
+	}
+	return truthyCond{left}, nil
+}
+
+func (p *exprParser) operand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber:
+		return literalOperand(t.text), nil
+	case tokColumn:
+		ref, err := ParseColumnRef(t.text)
+		if err != nil {
+			return nil, err
+		}
+		p.expr.columns = append(p.expr.columns, ref)
+		return columnOperand{slot: len(p.expr.columns) - 1}, nil
+	}
+	return nil, fmt.Errorf("expected a column or a value, found %s", t)
+}
@@ -0,0 +1,88 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExprFilter(t *testing.T) {
+	const input = "name,status,latency\n# requests\napi,ok,300\nweb,error,250\ndb,error,90\ncache,,5"
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: `status != "ok" && latency > 200`, want: []string{"web"}},
+		{expr: `$3 >= 250 || name == 'db'`, want: []string{"api", "web", "db"}},
+		{expr: `!(status == "error") && status`, want: []string{"api"}},
+		{expr: `name =~ "^(api|db)$"`, want: []string{"api", "db"}},
+		{expr: `${name} !~ "a"`, want: []string{"web", "db"}},
+		{expr: `latency < 100`, want: []string{"db", "cache"}},
+		{expr: `status > "error"`, want: []string{"api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpr() error = %v", err)
+			}
+			table, _ := ParseTable(input, ",")
+			table.MarkHeader(1)
+			table.Skip(1)
+			if err := table.Filter(e); err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			var got []string
+			for _, row := range table.Rows {
+				if row.Kind == RowData {
+					got = append(got, row.Cells[0])
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() kept %q, want %q", got, tt.want)
+			}
+			if len(table.Rows) != len(tt.want)+2 {
+				t.Errorf("Filter() dropped the header or passthrough rows: %+v", table.Rows)
+			}
+		})
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for _, expr := range []string{
+		`status ==`,
+		`(status == "ok"`,
+		`status == "ok`,
+		`$ > 1`,
+		`$0 > 1`,
+		`name =~ other`,
+		`name =~ "("`,
+		`a == 1 b`,
+		`a # 1`,
+	} {
+		if _, err := ParseExpr(expr); err == nil {
+			t.Errorf("ParseExpr(%q) expected error", expr)
+		}
+	}
+
+	e, _ := ParseExpr(`city == "rome"`)
+	table, _ := ParseTable("name,age\njohn,30", ",")
+	table.MarkHeader(1)
+	if err := table.Filter(e); err == nil {
+		t.Error("Filter() expected error for unknown column")
+	}
+}
+
+func TestFormatterWhere(t *testing.T) {
+	e, _ := ParseExpr(`$2 > 20`)
+	f, _ := New(WithDelimiter(","), WithOutputDelimiter("|"), WithWhere(e))
+	got, err := f.Format("a very long name,10\njohn,30\namy,25")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "john | 30\namy  | 25"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,418 @@
+package vsf
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formatter formats text with a fixed Config. It is safe for concurrent
+// use and can be reused for any number of inputs.
+//
+// Format runs the whole pipeline:
+//
+//	parse -> skip lines -> sections -> undelimited lines -> header -> filter -> sort -> group -> footer -> keys -> squash -> transpose -> max widths -> numbering -> separators -> page header -> render
+type Formatter struct {
+	config   Config
+	parser   Parser
+	renderer Renderer
+}
+
+// New returns a Formatter configured by opts. Without options it aligns
+// columns split on ":", or on "," with the csv input.
+//
+// Example:
+//
+//	f, _ := New(WithDelimiter(","), WithOutputDelimiter("|"), WithHeaderLines(1))
+//	out, _ := f.Format("name,age\njohn,30")
+func New(opts ...Option) (*Formatter, error) {
+	return newFormatter(Config{}, opts...)
+}
+
+// newFormatter returns a Formatter configured by opts applied over a
+// copy of config.
+func newFormatter(config Config, opts ...Option) (*Formatter, error) {
+	config.own()
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return nil, err
+		}
+	}
+	config.own()
+
+	f := &Formatter{config: config, parser: config.Parser, renderer: config.Renderer}
+
+	if f.parser == nil && config.Code {
+		if config.Delimiter == AutoDelimiter {
+			return nil, fmt.Errorf("code mode needs a delimiter")
+		}
+		f.parser = CodeParser{Delimiter: f.delimiter(), Comments: config.CodeComments}
+	}
+	if f.parser == nil && !f.detectsDelimiter() {
+		parser, err := NewParser(f.inputName(), ParserOptions{Delimiter: f.delimiter()})
+		if err != nil {
+			return nil, err
+		}
+		f.parser = parser
+	}
+
+	if f.renderer == nil {
+		renderer, err := f.newRenderer(f.outputDelimiter())
+		if err != nil {
+			return nil, err
+		}
+		f.renderer = renderer
+	}
+
+	// Only the text renderer writes hidden keys, and transposed rows
+	// have none
+	if config.KeyDelimiter != "" {
+		if _, ok := f.renderer.(TextRenderer); !ok {
+			return nil, fmt.Errorf("hidden keys need the text output")
+		}
+		if config.Transpose {
+			return nil, fmt.Errorf("hidden keys can't be transposed")
+		}
+	}
+
+	return f, nil
+}
+
+// own replaces the slices and maps of c with copies, so neither options
+// nor callers can change a formatter in use.
+func (c *Config) own() {
+	c.CodeComments = slices.Clone(c.CodeComments)
+	c.SkipLines = slices.Clone(c.SkipLines)
+	c.SkipMatch = slices.Clone(c.SkipMatch)
+	c.SectionMatch = slices.Clone(c.SectionMatch)
+	c.Separators = slices.Clone(c.Separators)
+	for i := range c.Separators {
+		c.Separators[i].Lines = slices.Clone(c.Separators[i].Lines)
+	}
+	c.Where = slices.Clone(c.Where)
+	c.Sort = slices.Clone(c.Sort)
+	c.Footer = slices.Clone(c.Footer)
+	c.Squash = slices.Clone(c.Squash)
+	c.KeyColumns = slices.Clone(c.KeyColumns)
+	if c.Group != nil {
+		group := *c.Group
+		group.Subtotals = slices.Clone(group.Subtotals)
+		c.Group = &group
+	}
+	c.Align = maps.Clone(c.Align)
+	c.MinWidth = maps.Clone(c.MinWidth)
+	c.MaxWidth = maps.Clone(c.MaxWidth)
+}
+
+// Config returns a copy of the formatter's settings. Changing it leaves
+// the formatter as it is.
+func (f *Formatter) Config() Config {
+	c := f.config
+	c.own()
+	return c
+}
+
+// Table parses input and lays it out, without rendering.
+func (f *Formatter) Table(input string) (*Table, error) {
+	t, _, err := f.table(input, 0)
+	return t, err
+}
+
+// table is Table with the data rows numbered after those of the first
+// blocks above input. It also returns how many data rows input has
+// before any is filtered out.
+func (f *Formatter) table(input string, first int) (*Table, int, error) {
+	c := f.config
+
+	parser := f.parser
+	if parser == nil {
+		detected, ok := DetectDelimiter(input)
+		if !ok {
+			return nil, 0, fmt.Errorf("could not detect delimiter")
+		}
+		f.logf("vsf: detected delimiter %s (%d columns)\n", detected, detected.Columns)
+		parser = detected.Parser()
+	}
+
+	t, err := parser.Parse(input)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	t.Skip(c.SkipLines.Resolve(t.LineCount())...)
+	for _, re := range c.SkipMatch {
+		t.SkipMatch(re)
+	}
+	for _, re := range c.SectionMatch {
+		t.SectionMatch(re)
+	}
+	switch c.Undelimited {
+	case UndelimitedPassthrough:
+		t.PassUndelimited()
+	case UndelimitedSection:
+		t.SectionUndelimited()
+	}
+
+	switch {
+	case c.HeaderLines > 0:
+		t.MarkHeader(c.HeaderLines)
+	case c.HeaderAuto && t.HeaderLine() < 0:
+		detected := DetectHeader(t)
+		if detected {
+			t.MarkHeader(1)
+		}
+		f.logf("vsf: header row detected: %v\n", detected)
+	}
+	t.HeaderMode = c.HeaderMode
+	rows := 0
+	for _, row := range t.Rows {
+		if row.Kind == RowData {
+			rows++
+		}
+	}
+	for _, e := range c.Where {
+		if err := t.Filter(e); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := t.Sort(c.Sort...); err != nil {
+		return nil, 0, err
+	}
+	if c.Group != nil {
+		if err := t.Group(*c.Group); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := t.AddFooter(c.FooterSep, c.Footer...); err != nil {
+		return nil, 0, err
+	}
+	if c.KeyDelimiter != "" {
+		if err := t.SetKeys(c.KeyColumns...); err != nil {
+			return nil, 0, err
+		}
+	}
+	if len(c.Squash) > 0 {
+		if err := t.Squash(c.Ditto, c.Squash...); err != nil {
+			return nil, 0, err
+		}
+	}
+	if c.Transpose {
+		t.Transpose()
+	}
+	t.SectionStyle, t.SectionChar = c.SectionStyle, c.SectionChar
+	f.truncate(t)
+	if c.Numbering {
+		t.Number(c.NumberFrom + first)
+	}
+
+	if c.HeaderSep != "" && t.HeaderLine() >= 0 {
+		t.InsertSeparator(t.HeaderLine(), c.HeaderSep)
+	}
+	t.AddSeparators(c.Separators...)
+
+	t.RepeatHeader(c.PageSize)
+	return t, rows, nil
+}
+
+// Columns computes the column metadata of t with the configured
+// alignment and minimum widths applied. With Numbering the index column
+// is right aligned and named IndexHeader, and the settings and the
+// numbers naming untitled columns refer to the columns after it.
+func (f *Formatter) Columns(t *Table) []Column {
+	cols := t.Columns()
+	offset := 0
+	if f.config.Numbering && len(cols) > 0 {
+		cols[0].Align = AlignRight
+		cols[0].Name = IndexHeader
+		offset = 1
+	}
+	for i := offset; i < len(cols); i++ {
+		if offset > 0 && cols[i].Name == "" {
+			cols[i].Name = strconv.Itoa(i - offset + 1)
+		}
+		cols[i].Align = f.config.Align[i-offset]
+		cols[i].Width = max(cols[i].Width, f.config.MinWidth[i-offset])
+	}
+	return cols
+}
+
+// Render formats input and writes it to w. With Blocks set, each block
+// of the input is parsed and laid out as a table of its own. With
+// Directives set, only the regions they mark are. See WithDirectives.
+func (f *Formatter) Render(w io.Writer, input string) error {
+	if f.config.Directives {
+		return f.renderDirectives(w, input)
+	}
+	if !f.config.Blocks {
+		_, err := f.render(w, input, 0)
+		return err
+	}
+
+	// Numbering runs on from one block to the next, so an index still
+	// points to a data row of the whole input
+	tables, rows := 0, 0
+	for _, b := range splitBlocks(input, f.config.BlockStart) {
+		if b.blank {
+			if _, err := io.WriteString(w, b.text+"\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		n, err := f.render(w, b.text, rows)
+		if err != nil {
+			return err
+		}
+		rows += n
+		tables++
+	}
+	if tables == 0 {
+		return ErrEmptyInput
+	}
+	return nil
+}
+
+// render formats input as a single table and writes it to w, numbering
+// its data rows after the first ones above it. It returns the number of
+// data rows in input. In code mode every run of lines sharing their
+// indentation gets its own widths.
+func (f *Formatter) render(w io.Writer, input string, first int) (int, error) {
+	t, rows, err := f.table(input, first)
+	if err != nil {
+		return 0, err
+	}
+
+	// Without an output delimiter, cells are joined by the detected one
+	renderer := f.renderer
+	if f.detectsDelimiter() && f.config.OutputDelimiter == "" && f.config.Renderer == nil {
+		if d, ok := DetectDelimiter(input); ok && !d.Whitespace {
+			if renderer, err = f.newRenderer(d.Delimiter); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if !f.config.Code {
+		return rows, renderer.Render(w, t, f.Columns(t))
+	}
+
+	for _, run := range t.Runs() {
+		if err := renderer.Render(w, run, f.Columns(run)); err != nil {
+			return 0, err
+		}
+	}
+	return rows, nil
+}
+
+// Format formats input and returns it without a trailing newline.
+func (f *Formatter) Format(input string) (string, error) {
+	var b strings.Builder
+	if err := f.Render(&b, input); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// truncate shortens cells wider than the configured max width, marking
+// the cut with "...".
+func (f *Formatter) truncate(t *Table) {
+	if len(f.config.MaxWidth) == 0 {
+		return
+	}
+
+	for i, row := range t.Rows {
+		if !row.aligned() {
+			continue
+		}
+		var cells []string
+		for col, cell := range row.Cells {
+			limit, ok := f.config.MaxWidth[col]
+			if !ok || len(cell) <= limit {
+				continue
+			}
+			if cells == nil {
+				cells = slices.Clone(row.Cells)
+			}
+			cells[col] = truncateCell(cell, limit)
+		}
+		if cells != nil {
+			t.Rows[i].Cells = cells
+		}
+	}
+}
+
+// truncateCell cuts cell to at most limit bytes on a rune boundary,
+// ending it with "..." when there is room for it.
+func truncateCell(cell string, limit int) string {
+	const ellipsis = "..."
+	if limit > len(ellipsis) {
+		cell = cell[:limit-len(ellipsis)]
+		for !utf8.ValidString(cell) {
+			cell = cell[:len(cell)-1]
+		}
+		return cell + ellipsis
+	}
+
+	cell = cell[:max(limit, 0)]
+	for !utf8.ValidString(cell) {
+		cell = cell[:len(cell)-1]
+	}
+	return cell
+}
+
+// detectsDelimiter reports whether the delimiter is detected per input.
+func (f *Formatter) detectsDelimiter() bool {
+	return f.config.Delimiter == AutoDelimiter && f.inputName() == DefaultParser
+}
+
+// inputName returns the configured parser name.
+func (f *Formatter) inputName() string {
+	if f.config.Input == "" {
+		return DefaultParser
+	}
+	return f.config.Input
+}
+
+// delimiter returns the input delimiter, defaulting on the parser: CSV
+// is split on commas and everything else on colons.
+func (f *Formatter) delimiter() string {
+	switch {
+	case f.config.Delimiter != "":
+		return f.config.Delimiter
+	case f.inputName() == "csv":
+		return ","
+	default:
+		return ":"
+	}
+}
+
+// newRenderer returns the configured renderer, writing delimiter between
+// cells.
+func (f *Formatter) newRenderer(delimiter string) (Renderer, error) {
+	name := f.config.Output
+	if name == "" {
+		name = DefaultRenderer
+	}
+	return NewRenderer(name, RenderOptions{
+		Delimiter:    delimiter,
+		HeaderStyle:  f.config.HeaderStyle,
+		KeyDelimiter: f.config.KeyDelimiter,
+	})
+}
+
+// outputDelimiter falls back to the input delimiter when no output
+// delimiter is set. A detected delimiter is only known at render time.
+func (f *Formatter) outputDelimiter() string {
+	if f.config.OutputDelimiter != "" || f.config.Delimiter == AutoDelimiter {
+		return f.config.OutputDelimiter
+	}
+	return f.delimiter()
+}
+
+func (f *Formatter) logf(format string, args ...any) {
+	if f.config.Logf != nil {
+		f.config.Logf(format, args...)
+	}
+}
@@ -0,0 +1,257 @@
+package vsf
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFormatterCombinations(t *testing.T) {
+	const input = "REPORT\nname:age\njohn:30\n----\namy:25"
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Nothing",
+			opts: nil,
+			want: "REPORT\nname | age\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Undelimited lines aligned",
+			opts: []Option{WithUndelimited(UndelimitedAligned)},
+			want: "REPORT\nname   | age\njohn   | 30\n----\namy    | 25",
+		},
+		{
+			name: "Skip",
+			opts: []Option{WithSkipLines(0, 3)},
+			want: "REPORT\nname | age\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Separator",
+			opts: []Option{WithSeparator(1, "=")},
+			want: "REPORT\nname | age\n=====|====\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Skip and separator",
+			opts: []Option{WithSkipLines(0, 3), WithSeparator(1, "=")},
+			want: "REPORT\nname | age\n=====|====\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Skip and header",
+			opts: []Option{WithSkipLines(0, 3), WithHeaderLines(1), WithHeaderMode(HeaderVerbatim)},
+			want: "REPORT\nname:age\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Skip, header and separator",
+			opts: []Option{WithSkipLines(0, 3), WithHeaderLines(1), WithHeaderSeparator("-")},
+			want: "REPORT\nname | age\n-----|----\njohn | 30\n----\namy  | 25",
+		},
+		{
+			name: "Skip, auto header and page",
+			opts: []Option{WithSkipLines(0, 3), WithHeaderAuto(), WithHeaderSeparator("-"), WithPageSize(1)},
+			want: "REPORT\nname | age\n-----|----\njohn | 30\n----\nname | age\n-----|----\namy  | 25",
+		},
+		{
+			name: "Header excluded and separator",
+			opts: []Option{WithSkipLines(0, 3), WithHeaderLines(1), WithHeaderMode(HeaderExcluded), WithSeparator(2, "~")},
+			want: "REPORT\nname:age\njohn | 30\n~~~~~|~~~\n----\namy  | 25",
+		},
+		{
+			name: "Markdown output with skip and header",
+			opts: []Option{WithSkipLines(0, 3), WithHeaderLines(1), WithOutput("markdown")},
+			want: "| name | age |\n| ---- | --- |\n| john | 30  |\n| amy  | 25  |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(append([]Option{WithOutputDelimiter("|")}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := f.Format(input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterSkipMatch(t *testing.T) {
+	const input = "WARN: deprecated flag\nname:age\n  # comment: ignored\njohn:30\n// other: style"
+
+	f, err := New(
+		WithOutputDelimiter("|"),
+		WithSkipMatch(regexp.MustCompile(`^WARN: `)),
+		WithComment("#"),
+		WithComment("//"),
+		WithSeparator(1, "-"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := "WARN: deprecated flag\nname | age\n-----|----\n  # comment: ignored\njohn | 30\n// other: style"
+	if got, _ := f.Format(input); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	if _, err := New(WithComment("")); err == nil {
+		t.Error("New() expected error for empty comment prefix")
+	}
+}
+
+func TestFormatterAutoDelimiter(t *testing.T) {
+	var logs []string
+	f, _ := New(
+		WithDelimiter(AutoDelimiter),
+		WithHeaderAuto(),
+		WithLogf(func(format string, args ...any) {
+			logs = append(logs, format)
+		}),
+	)
+
+	got, err := f.Format("name,age\njohn,30")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "name , age\njohn , 30"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if len(logs) != 2 || !strings.Contains(logs[0], "delimiter") {
+		t.Errorf("Logf calls = %q", logs)
+	}
+
+	if _, err := f.Format("nothing"); err == nil {
+		t.Error("Format() expected error when no delimiter is detected")
+	}
+}
+
+func TestFormatterDefaultDelimiter(t *testing.T) {
+	const input = "name,age\n\"doe, j\",30"
+
+	f, _ := New(WithInput("csv"), WithOutputDelimiter("|"))
+	if got, _ := f.Format(input); got != "name   | age\ndoe, j | 30" {
+		t.Errorf("csv: Format() = %q", got)
+	}
+
+	f, _ = New(WithInput("csv"), WithDelimiter(";"), WithOutputDelimiter("|"))
+	if got, _ := f.Format("a;b,c"); got != "a | b,c" {
+		t.Errorf("csv with a delimiter: Format() = %q", got)
+	}
+
+	f, _ = New(WithOutputDelimiter("|"))
+	if got, _ := f.Format("a:b,c"); got != "a | b,c" {
+		t.Errorf("delim: Format() = %q", got)
+	}
+}
+
+func TestFormatterAlignAndWidths(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Right aligned numbers",
+			opts: []Option{WithAlign(1, AlignRight)},
+			want: "item   |  qty\napple  |    3\nbanana | 1200",
+		},
+		{
+			name: "Centered column",
+			opts: []Option{WithAlign(0, AlignCenter)},
+			want: " item  | qty\napple  | 3\nbanana | 1200",
+		},
+		{
+			name: "Min width",
+			opts: []Option{WithMinWidth(0, 8)},
+			want: "item     | qty\napple    | 3\nbanana   | 1200",
+		},
+		{
+			name: "Max width",
+			opts: []Option{WithMaxWidth(0, 4), WithMaxWidth(1, 3)},
+			want: "item | qty\na... | 3\nb... | 120",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(append([]Option{WithOutputDelimiter("|")}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, _ := f.Format("item:qty\napple:3\nbanana:1200")
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterCustomParserAndRenderer(t *testing.T) {
+	f, err := New(WithParser(WhitespaceParser{}), WithRenderer(TextRenderer{Delimiter: "->"}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	got, _ := f.Format("a b\nccc d")
+	if want := "a   -> b\nccc -> d"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"Unknown input", []Option{WithInput("nope")}},
+		{"Unknown output", []Option{WithOutput("nope")}},
+		{"Invalid max width", []Option{WithMaxWidth(0, 0)}},
+		{"Invalid regex", []Option{WithInput("regex"), WithDelimiter("(")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil {
+				t.Error("New() expected error")
+			}
+		})
+	}
+}
+
+func TestFormatterConcurrent(t *testing.T) {
+	skip := []int{0}
+	f, _ := New(WithSkipLines(skip...), WithSeparator(1, "-"), WithAlign(1, AlignRight))
+	skip[0] = 1 // must not affect the formatter
+
+	const want = "banner\nname : age\n-----:----\njohn :  30"
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if got, _ := f.Format("banner\nname:age\njohn:30"); got != want {
+					t.Errorf("Format() = %q, want %q", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	c := f.Config()
+	c.Align[1] = AlignLeft
+	c.SkipLines[0] = LineRange{From: 1, To: 1}
+	c.Separators[0].Lines[0] = LineRange{From: 2, To: 2}
+	if got, _ := f.Format("banner\nname:age\njohn:30"); got != want {
+		t.Errorf("Format() after changing Config() = %q, want %q", got, want)
+	}
+}
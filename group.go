@@ -0,0 +1,95 @@
+package vsf
+
+import "fmt"
+
+// GroupStyle controls what sets the groups of a Grouping apart.
+type GroupStyle int
+
+const (
+	// GroupSection starts each group with a section row titled with its key.
+	GroupSection GroupStyle = iota
+	// GroupBlank separates the groups with a blank line.
+	GroupBlank
+)
+
+// String returns the name accepted by ParseGroupStyle.
+func (s GroupStyle) String() string {
+	switch s {
+	case GroupSection:
+		return "section"
+	case GroupBlank:
+		return "blank"
+	}
+	return fmt.Sprintf("GroupStyle(%d)", int(s))
+}
+
+// ParseGroupStyle parses "section" or "blank".
+func ParseGroupStyle(s string) (GroupStyle, error) {
+	for _, style := range []GroupStyle{GroupSection, GroupBlank} {
+		if s == style.String() {
+			return style, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid group style: %s", s)
+}
+
+// Grouping gathers the data rows sharing the value of a key column.
+type Grouping struct {
+	Column ColumnRef
+	Style  GroupStyle
+	// Subtotals are aggregates written in footer rows below each group.
+	Subtotals []Aggregate
+	// SepChar, when set, draws a separator above the subtotals.
+	SepChar string
+}
+
+// Group gathers the data rows by the value of their key column, groups
+// in order of first appearance and rows in their order within each
+// group. Rows above the first data row, like the header, stay on top;
+// other rows that aren't data follow the groups. A grand total is a
+// footer added afterwards, see AddFooter.
+func (t *Table) Group(g Grouping) error {
+	column, err := g.Column.resolve(t)
+	if err != nil {
+		return err
+	}
+
+	var (
+		top, rest []Row
+		keys      []string
+		groups    = make(map[string][]Row)
+	)
+	for _, row := range t.Rows {
+		switch {
+		case row.Kind == RowData:
+			key := cellAt(row, column)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], row)
+		case len(keys) == 0:
+			top = append(top, row)
+		default:
+			rest = append(rest, row)
+		}
+	}
+
+	rows := top
+	for i, key := range keys {
+		switch {
+		case g.Style == GroupSection:
+			rows = append(rows, Row{Kind: RowSection, Raw: key, Line: -1})
+		case i > 0:
+			rows = append(rows, Row{Kind: RowPassthrough, Line: -1})
+		}
+		rows = append(rows, groups[key]...)
+
+		subtotals, err := t.footer(groups[key], g.SepChar, g.Subtotals)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, subtotals...)
+	}
+	t.Rows = append(rows, rest...)
+	return nil
+}
@@ -0,0 +1,77 @@
+package vsf
+
+import "testing"
+
+func TestTableGroup(t *testing.T) {
+	const input = "service:item:cost\napi:cpu:10\nweb:cpu:5\napi:ram:2.5\ndb:disk:100"
+
+	tests := []struct {
+		name     string
+		grouping Grouping
+		want     string
+	}{
+		{
+			name:     "Sections",
+			grouping: Grouping{Column: ColumnRef{Name: "service"}},
+			want: "service | item | cost\n" +
+				"api\n" +
+				"api     | cpu  | 10\n" +
+				"api     | ram  | 2.5\n" +
+				"web\n" +
+				"web     | cpu  | 5\n" +
+				"db\n" +
+				"db      | disk | 100",
+		},
+		{
+			name: "Blank lines and subtotals",
+			grouping: Grouping{
+				Column:    ColumnRef{Index: 0},
+				Style:     GroupBlank,
+				Subtotals: []Aggregate{{AggSum, ColumnRef{Index: 2}}},
+				SepChar:   "-",
+			},
+			want: "service | item | cost\n" +
+				"api     | cpu  | 10\n" +
+				"api     | ram  | 2.5\n" +
+				"--------|------|------\n" +
+				"sum     |      | 12.5\n" +
+				"\n" +
+				"web     | cpu  | 5\n" +
+				"--------|------|------\n" +
+				"sum     |      | 5.0\n" +
+				"\n" +
+				"db      | disk | 100\n" +
+				"--------|------|------\n" +
+				"sum     |      | 100.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := ParseTable(input, ":")
+			table.MarkHeader(1)
+			table.SectionStyle = SectionLeft
+			if err := table.Group(tt.grouping); err != nil {
+				t.Fatalf("Group() error = %v", err)
+			}
+			if got := renderText(table, "|"); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterGroupTotal(t *testing.T) {
+	f, _ := New(
+		WithOutputDelimiter("|"),
+		WithGroup(Grouping{Column: ColumnRef{Index: 0}, Style: GroupBlank}),
+		WithFooter("=", Aggregate{AggSum, ColumnRef{Index: 1}}),
+	)
+	got, err := f.Format("b:1\na:2\nb:3")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "b   | 1\nb   | 3\n\na   | 2\n====|==\nsum | 6"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package vsf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HeaderMode controls how header rows are laid out.
+type HeaderMode int
+
+const (
+	// HeaderAligned aligns header rows with the body and counts them
+	// towards the column widths.
+	HeaderAligned HeaderMode = iota
+	// HeaderVerbatim writes header rows as-is, but still counts them
+	// towards the column widths.
+	HeaderVerbatim
+	// HeaderExcluded writes header rows as-is and leaves them out of the
+	// column widths, so a long header can't widen the body.
+	HeaderExcluded
+)
+
+// String returns the name accepted by ParseHeaderMode.
+func (m HeaderMode) String() string {
+	switch m {
+	case HeaderAligned:
+		return "aligned"
+	case HeaderVerbatim:
+		return "verbatim"
+	case HeaderExcluded:
+		return "excluded"
+	}
+	return fmt.Sprintf("HeaderMode(%d)", int(m))
+}
+
+// ParseHeaderMode parses "aligned", "verbatim" or "excluded".
+func ParseHeaderMode(s string) (HeaderMode, error) {
+	for _, mode := range []HeaderMode{HeaderAligned, HeaderVerbatim, HeaderExcluded} {
+		if s == mode.String() {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid header mode: %s", s)
+}
+
+// DetectHeader guesses whether the first data row of t is a header. It
+// looks at every column and votes:
+//
+//   - for, when the values below are numbers or dates and the first cell isn't
+//   - for, when three or more values below have the same length and the first cell doesn't
+//   - for, when the first cell is upper case and the values below aren't
+//   - against, when the first cell is a number or a date
+//
+// A first row with empty or repeated cells, or whose cells reappear in
+// their column, is never a header. At least two data rows are needed.
+//
+// Example:
+//
+//	t, _ := ParseTable("name:age\njohn:30\namy:25", ":")
+//	DetectHeader(t) // true
+func DetectHeader(t *Table) bool {
+	var rows [][]string
+	for _, row := range t.Rows {
+		if row.Kind == RowData {
+			rows = append(rows, row.Cells)
+		}
+	}
+	if len(rows) < 2 {
+		return false
+	}
+
+	first, body := rows[0], rows[1:]
+	seen := make(map[string]bool, len(first))
+	for _, cell := range first {
+		if cell == "" || seen[cell] {
+			return false
+		}
+		seen[cell] = true
+	}
+
+	votes := 0
+	for col, cell := range first {
+		var values []string
+		for _, row := range body {
+			if col < len(row) && row[col] != "" {
+				if row[col] == cell {
+					return false
+				}
+				values = append(values, row[col])
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		if isTyped(cell) {
+			votes--
+			continue
+		}
+
+		typed, sameLength, upper := true, true, true
+		for _, value := range values {
+			typed = typed && isTyped(value)
+			sameLength = sameLength && len(value) == len(values[0])
+			upper = upper && value == strings.ToUpper(value)
+		}
+		if typed {
+			votes++
+		}
+		if sameLength && len(values) >= 3 && len(cell) != len(values[0]) {
+			votes++
+		}
+		if !upper && cell == strings.ToUpper(cell) && cell != strings.ToLower(cell) {
+			votes++
+		}
+	}
+	return votes > 0
+}
+
+// MarkHeader gives the table n header rows at its top. Header rows the
+// parser already made, like the keys of JSON objects, count towards n;
+// the first data rows make up the rest.
+func (t *Table) MarkHeader(n int) {
+	for i := range t.Rows {
+		if n <= 0 {
+			return
+		}
+		switch t.Rows[i].Kind {
+		case RowHeader:
+			n--
+		case RowData:
+			t.Rows[i].Kind = RowHeader
+			n--
+		}
+	}
+}
+
+// HeaderLine returns the source line of the last header row, or -1 when
+// the table has no header.
+func (t *Table) HeaderLine() int {
+	line := -1
+	for _, row := range t.Rows {
+		if row.Kind == RowHeader {
+			line = row.Line
+		}
+	}
+	return line
+}
+
+// RepeatHeader repeats the header rows, along with the separators right
+// below them, after every n body rows so each page of output starts with
+// a header. It does nothing when n <= 0 or the table has no header.
+func (t *Table) RepeatHeader(n int) {
+	if n <= 0 {
+		return
+	}
+
+	var (
+		header []Row
+		end    = -1
+	)
+	for i, row := range t.Rows {
+		if row.Kind == RowHeader || (row.Kind == RowSeparator && end == i-1 && len(header) > 0) {
+			header = append(header, row)
+			end = i
+		} else if len(header) > 0 {
+			break
+		}
+	}
+	if len(header) == 0 {
+		return
+	}
+
+	rows := make([]Row, 0, len(t.Rows))
+	rows = append(rows, t.Rows[:end+1]...)
+	count := 0
+	for _, row := range t.Rows[end+1:] {
+		if row.Kind == RowData {
+			if count > 0 && count%n == 0 {
+				for _, h := range header {
+					h.Line = -1
+					rows = append(rows, h)
+				}
+			}
+			count++
+		}
+		rows = append(rows, row)
+	}
+	t.Rows = rows
+}
@@ -0,0 +1,145 @@
+package vsf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{
+			name:  "Numeric column below",
+			input: "name:age\njohn:30\namy:25",
+			want:  true,
+		},
+		{
+			name:  "Dates below",
+			input: "when,what\n2024-01-02,start\n2024-02-03,stop",
+			want:  true,
+		},
+		{
+			name:  "Upper case titles",
+			input: "NAME:CITY\njohn:rome\namy:paris",
+			want:  true,
+		},
+		{
+			name:  "Fixed length values",
+			input: "code:country\nIT:italy\nFR:france\nDE:germany",
+			want:  true,
+		},
+		{
+			name:  "Numeric first row",
+			input: "1:2\n3:4",
+			want:  false,
+		},
+		{
+			name:  "Plain text everywhere",
+			input: "john:rome\namy:paris\nbob:oslo",
+			want:  false,
+		},
+		{
+			name:  "Value repeated below",
+			input: "name:age\nname:30",
+			want:  false,
+		},
+		{
+			name:  "Single row",
+			input: "name:age",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := DetectDelimiter(tt.input)
+			table, _ := d.Parser().Parse(tt.input)
+			if got := DetectHeader(table); got != tt.want {
+				t.Errorf("DetectHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkHeader(t *testing.T) {
+	table, _ := ParseTable("banner\nname:age\njohn:30", ":")
+	table.Skip(0)
+	table.MarkHeader(1)
+
+	if table.Rows[1].Kind != RowHeader || table.Rows[2].Kind != RowData {
+		t.Errorf("Rows = %+v", table.Rows)
+	}
+	if got := table.HeaderLine(); got != 1 {
+		t.Errorf("HeaderLine() = %d, want 1", got)
+	}
+
+	table, _ = JSONParser{}.Parse(`{"a":1}` + "\n" + `{"a":2}`)
+	table.MarkHeader(1)
+	if table.Rows[0].Kind != RowHeader || table.Rows[1].Kind != RowData {
+		t.Errorf("JSON rows = %+v", table.Rows)
+	}
+}
+
+func TestHeaderStyle(t *testing.T) {
+	table, _ := ParseTable("name:age\njohn:30", ":")
+	table.MarkHeader(1)
+
+	style, err := LookupStyle("upper")
+	if err != nil {
+		t.Fatalf("LookupStyle() error = %v", err)
+	}
+
+	var b strings.Builder
+	TextRenderer{Delimiter: "|", HeaderStyle: style}.Render(&b, table, table.Columns())
+	if got, want := b.String(), "NAME | AGE\njohn | 30\n"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	if _, err := LookupStyle("blink"); err == nil {
+		t.Error("LookupStyle() expected error for unknown style")
+	}
+}
+
+func TestHeaderMode(t *testing.T) {
+	tests := []struct {
+		mode HeaderMode
+		want string
+	}{
+		{HeaderAligned, "id_number | name\n1         | john"},
+		{HeaderVerbatim, "id_number:name\n1         | john"},
+		{HeaderExcluded, "id_number:name\n1 | john"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			table, _ := ParseTable("id_number:name\n1:john", ":")
+			table.MarkHeader(1)
+			table.HeaderMode = tt.mode
+
+			if got := renderText(table, "|"); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+			if mode, err := ParseHeaderMode(tt.mode.String()); err != nil || mode != tt.mode {
+				t.Errorf("ParseHeaderMode(%q) = %v, %v", tt.mode, mode, err)
+			}
+		})
+	}
+}
+
+func TestRepeatHeader(t *testing.T) {
+	table, _ := ParseTable("h:h\na:1\nb:2\nc:3\nd:4\ne:5", ":")
+	table.MarkHeader(1)
+	table.InsertSeparator(0, "-")
+	table.RepeatHeader(2)
+
+	want := "h | h\n--|--\na | 1\nb | 2\nh | h\n--|--\nc | 3\nd | 4\nh | h\n--|--\ne | 5"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+	if table.Rows[5].Line != -1 {
+		t.Errorf("repeated header Line = %d, want -1", table.Rows[5].Line)
+	}
+}
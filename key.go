@@ -0,0 +1,81 @@
+package vsf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultKeyDelimiter separates the hidden key fields from each other
+// and from the formatted row. It is the ASCII unit separator, which
+// terminals don't print.
+const DefaultKeyDelimiter = "\x1f"
+
+// SetKeys sets the hidden key of every data row to the cells of columns,
+// or to its source line without any, so a selected row maps back to its
+// values however it is laid out. See RenderOptions.KeyDelimiter.
+func (t *Table) SetKeys(columns ...ColumnRef) error {
+	indexes := make([]int, len(columns))
+	for i, ref := range columns {
+		column, err := ref.resolve(t)
+		if err != nil {
+			return err
+		}
+		indexes[i] = column
+	}
+
+	for i, row := range t.Rows {
+		if row.Kind != RowData {
+			continue
+		}
+		if len(indexes) == 0 {
+			t.Rows[i].Key = []string{row.Raw}
+			continue
+		}
+		key := make([]string, len(indexes))
+		for j, column := range indexes {
+			key[j] = cellAt(row, column)
+		}
+		t.Rows[i].Key = key
+	}
+	return nil
+}
+
+// FzfArgs returns the fzf options splitting the output of a formatter
+// with a hidden key, so fzf shows the formatted rows and searches them
+// only. It returns nil without a hidden key.
+//
+// Example:
+//
+//	f, _ := New(WithHiddenKey())
+//	f.FzfArgs()
+//	// ["--delimiter=\x1f", "--with-nth=2.."]
+func (f *Formatter) FzfArgs() []string {
+	c := f.config
+	if c.KeyDelimiter == "" {
+		return nil
+	}
+	fields := max(len(c.KeyColumns), 1)
+	return []string{
+		"--delimiter=" + fzfPattern(c.KeyDelimiter),
+		"--with-nth=" + strconv.Itoa(fields+1) + "..",
+	}
+}
+
+// fzfPattern escapes s as a regular expression readable on a command
+// line, writing control characters as \xNN.
+func fzfPattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < ' ' || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r > 0x7f:
+			fmt.Fprintf(&b, `\x{%x}`, r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package vsf
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTableSetKeys(t *testing.T) {
+	table, _ := ParseTable("name:age:city\njohn:30:rome\n# staff\namy:25", ":")
+	table.MarkHeader(1)
+	table.Skip(2)
+	if err := table.SetKeys(ColumnRef{Name: "city"}, ColumnRef{Index: 0}); err != nil {
+		t.Fatalf("SetKeys() error = %v", err)
+	}
+
+	var b strings.Builder
+	r := TextRenderer{Delimiter: "|", KeyDelimiter: ";"}
+	if err := r.Render(&b, table, table.Columns()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := ";;name | age | city\n" +
+		"rome;john;john | 30  | rome\n" +
+		";;# staff\n" +
+		";amy;amy  | 25\n"
+	if got := b.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	if err := table.SetKeys(ColumnRef{Name: "nope"}); err == nil {
+		t.Error("SetKeys() expected error for unknown column")
+	}
+}
+
+func TestFormatterFzfArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"No key", nil, nil},
+		{"Source line", []Option{WithHiddenKey()}, []string{`--delimiter=\x1f`, "--with-nth=2.."}},
+		{"Columns", []Option{WithHiddenKey(ColumnRef{Index: 0}, ColumnRef{Index: 2})}, []string{`--delimiter=\x1f`, "--with-nth=3.."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := f.FzfArgs(); !slices.Equal(got, tt.want) {
+				t.Errorf("FzfArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHiddenKeyConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"Markdown", []Option{WithOutput("markdown")}},
+		{"Expanded", []Option{WithOutput("expanded")}},
+		{"Custom renderer", []Option{WithRenderer(MarkdownRenderer{})}},
+		{"Transpose", []Option{WithTranspose()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(append(tt.opts, WithHiddenKey())...); err == nil {
+				t.Error("New() expected error for a hidden key")
+			}
+			if _, err := New(tt.opts...); err != nil {
+				t.Errorf("New() without a hidden key error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFzfPattern(t *testing.T) {
+	for s, want := range map[string]string{"\x1f": `\x1f`, "|": `\|`, "│": `\x{2502}`} {
+		if got := fzfPattern(s); got != want {
+			t.Errorf("fzfPattern(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
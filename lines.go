@@ -0,0 +1,125 @@
+package vsf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of 0-based source lines. Negative
+// bounds count from the end, -1 being the last line.
+type LineRange struct {
+	From int
+	To   int
+	// OpenEnd extends the range to the last line, ignoring To.
+	OpenEnd bool
+}
+
+// LineSpec is a set of source lines, such as the lines to skip.
+type LineSpec []LineRange
+
+// Lines returns a spec selecting the given lines.
+func Lines(lines ...int) LineSpec {
+	spec := make(LineSpec, len(lines))
+	for i, line := range lines {
+		spec[i] = LineRange{From: line, To: line}
+	}
+	return spec
+}
+
+// ParseLineSpec parses comma-separated lines and ranges:
+//
+//	3      line 3
+//	-1     the last line
+//	0-2    lines 0 to 2
+//	5-     line 5 to the end
+//	2--2   line 2 to the second to last line
+//
+// Example:
+//
+//	ParseLineSpec("0-2,10,-1")
+//	// Lines 0, 1, 2, 10 and the last one
+func ParseLineSpec(s string) (LineSpec, error) {
+	var spec LineSpec
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		r, err := parseLineRange(part)
+		if err != nil {
+			return nil, err
+		}
+		spec = append(spec, r)
+	}
+	return spec, nil
+}
+
+// parseLineRange parses a single line or range of ParseLineSpec.
+func parseLineRange(s string) (LineRange, error) {
+	// Skip the sign of a negative start when looking for the range dash
+	sep := strings.Index(s[1:], "-")
+	if sep < 0 {
+		line, err := strconv.Atoi(s)
+		if err != nil {
+			return LineRange{}, fmt.Errorf("invalid line number: %s", s)
+		}
+		return LineRange{From: line, To: line}, nil
+	}
+	sep++
+
+	from, err := strconv.Atoi(strings.TrimSpace(s[:sep]))
+	if err != nil {
+		return LineRange{}, fmt.Errorf("invalid line range: %s", s)
+	}
+
+	end := strings.TrimSpace(s[sep+1:])
+	if end == "" {
+		return LineRange{From: from, OpenEnd: true}, nil
+	}
+	to, err := strconv.Atoi(end)
+	if err != nil {
+		return LineRange{}, fmt.Errorf("invalid line range: %s", s)
+	}
+	return LineRange{From: from, To: to}, nil
+}
+
+// Resolve returns the absolute line numbers selected by the spec in an
+// input of n lines, in spec order. Lines out of range are dropped.
+func (s LineSpec) Resolve(n int) []int {
+	var lines []int
+	for _, r := range s {
+		from, to := resolveLine(r.From, n), resolveLine(r.To, n)
+		if r.OpenEnd {
+			to = n - 1
+		}
+		for line := max(from, 0); line <= to && line < n; line++ {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Contains reports whether the spec selects line in an input of n lines.
+func (s LineSpec) Contains(line, n int) bool {
+	for _, r := range s {
+		from, to := resolveLine(r.From, n), resolveLine(r.To, n)
+		if r.OpenEnd {
+			to = n - 1
+		}
+		if line >= from && line <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLine turns a negative line, counted from the end, into an
+// absolute one.
+func resolveLine(line, n int) int {
+	if line < 0 {
+		return line + n
+	}
+	return line
+}
@@ -0,0 +1,88 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLineSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{
+			name:  "Valid numbers",
+			input: "1,3,5",
+			want:  []int{1, 3, 5},
+		},
+		{
+			name:  "Single number",
+			input: "2",
+			want:  []int{2},
+		},
+		{
+			name:  "Empty string",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "With spaces",
+			input: " 1 , 3 , 5 ",
+			want:  []int{1, 3, 5},
+		},
+		{
+			name:  "Range",
+			input: "0-2,8",
+			want:  []int{0, 1, 2, 8},
+		},
+		{
+			name:  "Open-ended range",
+			input: "7-",
+			want:  []int{7, 8, 9},
+		},
+		{
+			name:  "Negative index",
+			input: "0-2,-1",
+			want:  []int{0, 1, 2, 9},
+		},
+		{
+			name:  "Range with negative bounds",
+			input: "-3--2,5--4",
+			want:  []int{7, 8, 5, 6},
+		},
+		{
+			name:  "Out of range",
+			input: "12,-11,8-20",
+			want:  []int{8, 9},
+		},
+		{
+			name:    "Invalid number",
+			input:   "1,abc,3",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid range",
+			input:   "1-x",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseLineSpec(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := spec.Resolve(10); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve(10) = %v, want %v", got, tt.want)
+			}
+			for _, line := range tt.want {
+				if !spec.Contains(line, 10) {
+					t.Errorf("Contains(%d, 10) = false", line)
+				}
+			}
+		})
+	}
+}
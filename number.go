@@ -0,0 +1,41 @@
+package vsf
+
+import (
+	"slices"
+	"strconv"
+)
+
+// IndexHeader titles the index column added by Number in header rows.
+const IndexHeader = "#"
+
+// Number prepends an index column numbering each data row by its place
+// among the data rows of the input, from start, usually 0 or 1. The
+// index keeps pointing to the input row once rows are filtered out or
+// sorted. Header rows get IndexHeader and footer rows an empty cell.
+// Other rows, like passthrough lines, are neither counted nor changed.
+func (t *Table) Number(start int) {
+	// Filter only removes data rows, so the parsed rows above a data
+	// row that aren't data are those still in the table
+	var other []int
+	for _, row := range t.Rows {
+		if row.Kind != RowData && row.Line >= 0 {
+			other = append(other, row.Record)
+		}
+	}
+	slices.Sort(other)
+
+	for i, row := range t.Rows {
+		var index string
+		switch row.Kind {
+		case RowData:
+			above, _ := slices.BinarySearch(other, row.Record)
+			index = strconv.Itoa(start + row.Record - above)
+		case RowHeader:
+			index = IndexHeader
+		case RowFooter:
+		default:
+			continue
+		}
+		t.Rows[i].Cells = append([]string{index}, row.Cells...)
+	}
+}
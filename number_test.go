@@ -0,0 +1,85 @@
+package vsf
+
+import "testing"
+
+func TestTableNumber(t *testing.T) {
+	table, _ := ParseTable("name:age\n# staff\njohn:30\namy:25", ":")
+	table.MarkHeader(1)
+	table.Skip(1)
+	table.HeaderMode = HeaderAligned
+	if err := table.AddFooter("", Aggregate{AggSum, ColumnRef{Index: 1}}); err != nil {
+		t.Fatalf("AddFooter() error = %v", err)
+	}
+	table.Number(0)
+
+	want := "# | name | age\n" +
+		"# staff\n" +
+		"0 | john | 30\n" +
+		"1 | amy  | 25\n" +
+		"  | sum  | 55"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestFormatterNumberSourceOrder(t *testing.T) {
+	e, _ := ParseExpr("$2 > 20")
+	f, _ := New(
+		WithOutputDelimiter("|"),
+		WithWhere(e),
+		WithSort(SortKey{Column: ColumnRef{Index: 1}, Type: SortNumeric}),
+		WithNumbering(1),
+	)
+	got, err := f.Format("# staff\njohn:30\nbob:10\n# more\namy:25")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "# staff\n3 | amy  | 25\n# more\n1 | john | 30"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatterNumberRecords(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		input string
+		want  string
+	}{
+		{
+			name:  "Multi-line CSV record",
+			opts:  []Option{WithInput("csv")},
+			input: "a,\"x\ny\"\nb,2\nc,3",
+			want:  "1 | a | x\\ny\n2 | b | 2\n3 | c | 3",
+		},
+		{
+			name:  "Blocks",
+			opts:  []Option{WithBlocks()},
+			input: "a:1\nb:2\n\nc:3",
+			want:  "1 | a | 1\n2 | b | 2\n\n3 | c | 3",
+		},
+		{
+			name:  "Expanded output",
+			opts:  []Option{WithOutput("expanded")},
+			input: "john:30",
+			want:  "-[ RECORD 1 ]\n# | 1\n1 | john\n2 | 30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithOutputDelimiter("|"), WithNumbering(1)}, tt.opts...)
+			f, err := New(opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := f.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
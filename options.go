@@ -0,0 +1,353 @@
+package vsf
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Option configures a Formatter. See New.
+type Option func(*Config) error
+
+// WithDelimiter sets the delimiter that splits lines into cells.
+// AutoDelimiter detects it from each input.
+func WithDelimiter(delimiter string) Option {
+	return func(c *Config) error {
+		c.Delimiter = delimiter
+		return nil
+	}
+}
+
+// WithOutputDelimiter sets the delimiter written between cells.
+func WithOutputDelimiter(delimiter string) Option {
+	return func(c *Config) error {
+		c.OutputDelimiter = delimiter
+		return nil
+	}
+}
+
+// WithInput selects a registered parser by name.
+func WithInput(name string) Option {
+	return func(c *Config) error {
+		c.Input = name
+		return nil
+	}
+}
+
+// WithParser sets the parser, overriding WithInput.
+func WithParser(p Parser) Option {
+	return func(c *Config) error {
+		c.Parser = p
+		return nil
+	}
+}
+
+// WithOutput selects a registered renderer by name.
+func WithOutput(name string) Option {
+	return func(c *Config) error {
+		c.Output = name
+		return nil
+	}
+}
+
+// WithRenderer sets the renderer, overriding WithOutput.
+func WithRenderer(r Renderer) Option {
+	return func(c *Config) error {
+		c.Renderer = r
+		return nil
+	}
+}
+
+// WithCode aligns source and config files: leading indentation and
+// trailing comments starting with one of comments (default "//" and "#")
+// are kept, and only consecutive lines with the same indentation are
+// aligned together. The delimiter can't be detected in code mode.
+func WithCode(comments ...string) Option {
+	return func(c *Config) error {
+		c.Code = true
+		if len(comments) > 0 {
+			c.CodeComments = comments
+		}
+		return nil
+	}
+}
+
+// WithDirectives only aligns the regions between vsf:align-begin and
+// vsf:align-end comments, each with the options of its align-begin
+// line, or without any region everything outside vsf:off and vsf:on.
+// Every other line is written as-is. See ParseDirective.
+func WithDirectives() Option {
+	return func(c *Config) error {
+		c.Directives = true
+		return nil
+	}
+}
+
+// WithBlocks aligns each block of lines separated by blank lines on its
+// own widths. Blank lines are kept, and line numbers in other settings
+// count from the start of each block.
+func WithBlocks() Option {
+	return func(c *Config) error {
+		c.Blocks = true
+		return nil
+	}
+}
+
+// WithBlockStart aligns blocks as WithBlocks does, also starting a new
+// block at every line matching re, such as `^\[` for INI sections.
+func WithBlockStart(re *regexp.Regexp) Option {
+	return func(c *Config) error {
+		c.Blocks, c.BlockStart = true, re
+		return nil
+	}
+}
+
+// WithSkipLines writes the given 0-based source lines as-is and leaves
+// them out of the column widths. Negative lines count from the end.
+func WithSkipLines(lines ...int) Option {
+	return WithSkip(Lines(lines...))
+}
+
+// WithSkip is WithSkipLines for a line spec, see ParseLineSpec.
+func WithSkip(spec LineSpec) Option {
+	return func(c *Config) error {
+		c.SkipLines = append(c.SkipLines, spec...)
+		return nil
+	}
+}
+
+// WithSkipMatch writes the source lines matching re as-is and leaves
+// them out of the column widths.
+func WithSkipMatch(re *regexp.Regexp) Option {
+	return func(c *Config) error {
+		c.SkipMatch = append(c.SkipMatch, re)
+		return nil
+	}
+}
+
+// WithComment passes through comment lines, those starting with prefix
+// after optional blanks. It is a shorthand for WithSkipMatch.
+func WithComment(prefix string) Option {
+	return func(c *Config) error {
+		if prefix == "" {
+			return fmt.Errorf("empty comment prefix")
+		}
+		c.SkipMatch = append(c.SkipMatch, regexp.MustCompile(`^\s*`+regexp.QuoteMeta(prefix)))
+		return nil
+	}
+}
+
+// WithUndelimited sets how lines without the delimiter are laid out. By
+// default they pass through verbatim.
+func WithUndelimited(mode UndelimitedMode) Option {
+	return func(c *Config) error {
+		c.Undelimited = mode
+		return nil
+	}
+}
+
+// WithSectionMatch turns source lines matching re into section rows,
+// titles spanning the whole table.
+func WithSectionMatch(re *regexp.Regexp) Option {
+	return func(c *Config) error {
+		c.SectionMatch = append(c.SectionMatch, re)
+		return nil
+	}
+}
+
+// WithSectionStyle sets how section rows are laid out. char frames them
+// with SectionFramed; empty uses DefaultSectionChar.
+func WithSectionStyle(style SectionStyle, char string) Option {
+	return func(c *Config) error {
+		c.SectionStyle, c.SectionChar = style, char
+		return nil
+	}
+}
+
+// WithSeparator adds a separator line drawn with char after a 0-based
+// source line. Negative lines count from the end.
+func WithSeparator(afterLine int, char string) Option {
+	return WithSeparators(Separator{Lines: Lines(afterLine), Char: char})
+}
+
+// WithSeparators adds separator rules. See Separator.
+func WithSeparators(seps ...Separator) Option {
+	return func(c *Config) error {
+		c.Separators = append(c.Separators, seps...)
+		return nil
+	}
+}
+
+// WithHeaderLines keeps the first n data rows as header.
+func WithHeaderLines(n int) Option {
+	return func(c *Config) error {
+		c.HeaderLines = n
+		return nil
+	}
+}
+
+// WithHeaderAuto detects a single header row with DetectHeader.
+func WithHeaderAuto() Option {
+	return func(c *Config) error {
+		c.HeaderAuto = true
+		return nil
+	}
+}
+
+// WithHeaderMode sets how header rows are laid out.
+func WithHeaderMode(mode HeaderMode) Option {
+	return func(c *Config) error {
+		c.HeaderMode = mode
+		return nil
+	}
+}
+
+// WithHeaderSeparator draws a separator with char below the header.
+func WithHeaderSeparator(char string) Option {
+	return func(c *Config) error {
+		c.HeaderSep = char
+		return nil
+	}
+}
+
+// WithHeaderStyle decorates the header rows.
+func WithHeaderStyle(style Style) Option {
+	return func(c *Config) error {
+		c.HeaderStyle = style
+		return nil
+	}
+}
+
+// WithPageSize repeats the header every n data rows.
+func WithPageSize(n int) Option {
+	return func(c *Config) error {
+		c.PageSize = n
+		return nil
+	}
+}
+
+// WithWhere keeps only the data rows for which e is true, see ParseExpr.
+// Filtered rows don't affect the column widths.
+func WithWhere(e *Expr) Option {
+	return func(c *Config) error {
+		c.Where = append(c.Where, e)
+		return nil
+	}
+}
+
+// WithSort orders the data rows by keys, see Table.Sort. Header and
+// passthrough lines keep their place.
+func WithSort(keys ...SortKey) Option {
+	return func(c *Config) error {
+		c.Sort = append(c.Sort, keys...)
+		return nil
+	}
+}
+
+// WithGroup gathers the data rows by the key column of g, setting the
+// groups apart and adding their subtotals. See Table.Group.
+func WithGroup(g Grouping) Option {
+	return func(c *Config) error {
+		c.Group = &g
+		return nil
+	}
+}
+
+// WithFooter adds footer rows with aggregates of the data rows, such as
+// totals, below a separator drawn with sepChar. An empty sepChar draws no
+// separator. See Table.AddFooter.
+func WithFooter(sepChar string, aggs ...Aggregate) Option {
+	return func(c *Config) error {
+		c.FooterSep = sepChar
+		c.Footer = append(c.Footer, aggs...)
+		return nil
+	}
+}
+
+// WithSquash blanks out the cells of columns equal to the cell above,
+// writing ditto instead, so rows sharing keys read like a tree. See
+// Table.Squash.
+func WithSquash(ditto string, columns ...ColumnRef) Option {
+	return func(c *Config) error {
+		c.Ditto = ditto
+		c.Squash = append(c.Squash, columns...)
+		return nil
+	}
+}
+
+// WithHiddenKey starts each row with hidden key fields ended by
+// DefaultKeyDelimiter: the cells of columns, or the source line without
+// any. Tools like fzf can then show the formatted row and output the
+// values it came from, see Formatter.FzfArgs. Only the text output
+// writes keys, so New rejects them with another one or WithTranspose.
+func WithHiddenKey(columns ...ColumnRef) Option {
+	return func(c *Config) error {
+		c.KeyDelimiter = DefaultKeyDelimiter
+		c.KeyColumns = append(c.KeyColumns, columns...)
+		return nil
+	}
+}
+
+// WithTranspose swaps rows and columns, so each column of the input is
+// written as a row. For one record per block use the "expanded" output.
+func WithTranspose() Option {
+	return func(c *Config) error {
+		c.Transpose = true
+		return nil
+	}
+}
+
+// WithNumbering prepends a right aligned index column numbering the data
+// rows in input order from start, usually 0 or 1, so an index picked
+// from sorted or filtered output still names its input row. See
+// Table.Number.
+func WithNumbering(start int) Option {
+	return func(c *Config) error {
+		c.Numbering, c.NumberFrom = true, start
+		return nil
+	}
+}
+
+// WithAlign sets the alignment of a 0-based column.
+func WithAlign(column int, align Align) Option {
+	return func(c *Config) error {
+		if c.Align == nil {
+			c.Align = map[int]Align{}
+		}
+		c.Align[column] = align
+		return nil
+	}
+}
+
+// WithMinWidth pads a 0-based column to at least width.
+func WithMinWidth(column, width int) Option {
+	return func(c *Config) error {
+		if c.MinWidth == nil {
+			c.MinWidth = map[int]int{}
+		}
+		c.MinWidth[column] = width
+		return nil
+	}
+}
+
+// WithMaxWidth truncates the cells of a 0-based column to at most width.
+func WithMaxWidth(column, width int) Option {
+	return func(c *Config) error {
+		if width < 1 {
+			return fmt.Errorf("invalid max width: %d", width)
+		}
+		if c.MaxWidth == nil {
+			c.MaxWidth = map[int]int{}
+		}
+		c.MaxWidth[column] = width
+		return nil
+	}
+}
+
+// WithLogf receives reports of detected settings, such as the delimiter
+// picked by AutoDelimiter.
+func WithLogf(logf func(format string, args ...any)) Option {
+	return func(c *Config) error {
+		c.Logf = logf
+		return nil
+	}
+}
@@ -0,0 +1,476 @@
+package vsf
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultParser is the name of the delimiter parser.
+const DefaultParser = "delim"
+
+// ErrEmptyInput is returned when the input has nothing to format.
+var ErrEmptyInput = errors.New("empty input")
+
+// Parser turns raw input into a Table of rows and cells. Rows are in
+// input order, with their Line and Record set.
+type Parser interface {
+	Parse(input string) (*Table, error)
+}
+
+// ParserOptions are the settings passed to a ParserFactory.
+type ParserOptions struct {
+	// Delimiter separates cells. Parsers that split on a pattern read it
+	// as a regular expression.
+	Delimiter string
+}
+
+// ParserFactory creates a Parser configured with opts.
+type ParserFactory func(opts ParserOptions) (Parser, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]ParserFactory{
+		"delim": func(opts ParserOptions) (Parser, error) {
+			return DelimiterParser{Delimiter: opts.Delimiter}, nil
+		},
+		"regex": func(opts ParserOptions) (Parser, error) {
+			re, err := regexp.Compile(opts.Delimiter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid delimiter pattern: %w", err)
+			}
+			return RegexParser{Pattern: re}, nil
+		},
+		"whitespace": func(ParserOptions) (Parser, error) {
+			return WhitespaceParser{}, nil
+		},
+		"csv": func(opts ParserOptions) (Parser, error) {
+			comma, size := utf8.DecodeRuneInString(opts.Delimiter)
+			if size == 0 || size != len(opts.Delimiter) {
+				comma = ','
+			}
+			return CSVParser{Comma: comma}, nil
+		},
+		"json": func(ParserOptions) (Parser, error) {
+			return JSONParser{}, nil
+		},
+		"fixed": func(ParserOptions) (Parser, error) {
+			return FixedWidthParser{}, nil
+		},
+	}
+)
+
+// RegisterParser makes a parser available by name to NewParser and the
+// CLI -input flag. Registering an existing name replaces it.
+func RegisterParser(name string, factory ParserFactory) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[name] = factory
+}
+
+// NewParser returns the parser registered under name.
+func NewParser(name string, opts ParserOptions) (Parser, error) {
+	parsersMu.RLock()
+	factory, ok := parsers[name]
+	parsersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown parser: %s", name)
+	}
+	return factory(opts)
+}
+
+// Parsers returns the sorted names of all registered parsers.
+func Parsers() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitTable trims input, splits it into lines and each line into cells
+// with split. It is the shared base of the line oriented parsers.
+func splitTable(input string, split func(line string) ([]string, bool)) (*Table, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+	return linesTable(strings.Split(input, "\n"), split), nil
+}
+
+// linesTable builds a table with one data row per line. split returns
+// the cells of a line and whether it found a delimiter in it.
+func linesTable(lines []string, split func(line string) ([]string, bool)) *Table {
+	t := &Table{Rows: make([]Row, len(lines))}
+	for i, line := range lines {
+		cells, delimited := split(line)
+		t.Rows[i] = Row{
+			Kind:        RowData,
+			Cells:       cells,
+			Raw:         line,
+			Line:        i,
+			Record:      i,
+			Undelimited: !delimited,
+		}
+	}
+	return t
+}
+
+// DelimiterParser splits each line on a literal delimiter, respecting
+// quotes. See ParseLine.
+type DelimiterParser struct {
+	Delimiter string
+}
+
+// Parse implements Parser.
+func (p DelimiterParser) Parse(input string) (*Table, error) {
+	return splitTable(input, func(line string) ([]string, bool) {
+		return parseLine(line, p.Delimiter)
+	})
+}
+
+// RegexParser splits each line on every match of Pattern.
+type RegexParser struct {
+	Pattern *regexp.Regexp
+}
+
+// Parse implements Parser.
+func (p RegexParser) Parse(input string) (*Table, error) {
+	return splitTable(input, func(line string) ([]string, bool) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil, false
+		}
+
+		cells := p.Pattern.Split(line, -1)
+		for i, cell := range cells {
+			cells[i] = strings.TrimSpace(cell)
+		}
+		return cells, p.Pattern.MatchString(line)
+	})
+}
+
+// WhitespaceParser splits each line on runs of spaces and tabs,
+// respecting quotes.
+type WhitespaceParser struct{}
+
+// Parse implements Parser.
+func (WhitespaceParser) Parse(input string) (*Table, error) {
+	return splitTable(input, func(line string) ([]string, bool) {
+		cells := splitWhitespace(line)
+		return cells, len(cells) > 1
+	})
+}
+
+// splitWhitespace splits line on runs of blanks outside of quotes.
+func splitWhitespace(line string) []string {
+	var (
+		result   []string
+		current  strings.Builder
+		inQuotes bool
+	)
+
+	for i := 0; i < len(line); i++ {
+		char := line[i]
+		switch {
+		case char == '"' || char == '\'':
+			inQuotes = !inQuotes
+			current.WriteByte(char)
+		case !inQuotes && (char == ' ' || char == '\t'):
+			if current.Len() > 0 {
+				result = append(result, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(char)
+		}
+	}
+
+	if current.Len() > 0 {
+		result = append(result, current.String())
+	}
+	return result
+}
+
+// CSVParser reads RFC 4180 CSV. Unlike DelimiterParser, quotes are
+// removed from the cells and quoted fields may span several lines, their
+// line breaks written as \n.
+type CSVParser struct {
+	Comma rune
+}
+
+// Parse implements Parser.
+func (p CSVParser) Parse(input string) (*Table, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+
+	r := csv.NewReader(strings.NewReader(input))
+	if p.Comma != 0 {
+		r.Comma = p.Comma
+	}
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	lines := strings.Split(input, "\n")
+	t := &Table{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		for i, cell := range record {
+			record[i] = escapeNewlines(strings.TrimSpace(cell))
+		}
+		t.Rows = append(t.Rows, Row{
+			Kind:        RowData,
+			Cells:       record,
+			Raw:         lines[line-1],
+			Line:        line - 1,
+			Record:      len(t.Rows),
+			Undelimited: len(record) < 2,
+		})
+	}
+	return t, nil
+}
+
+// JSONParser reads a single JSON array, or a stream of JSON values such
+// as JSON Lines. Each element becomes a row: arrays map to cells in order,
+// objects map to cells under a header row made of their keys in order of
+// first appearance. Nested values are written as compact JSON and line
+// breaks in strings as \n.
+type JSONParser struct{}
+
+// Parse implements Parser.
+func (JSONParser) Parse(input string) (*Table, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+
+	var records []json.RawMessage
+	dec := json.NewDecoder(strings.NewReader(input))
+	for {
+		var record json.RawMessage
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	// A single top-level array holds the records
+	if len(records) == 1 && bytes.HasPrefix(records[0], []byte("[")) {
+		var elements []json.RawMessage
+		if err := json.Unmarshal(records[0], &elements); err != nil {
+			return nil, err
+		}
+		records = elements
+	}
+
+	var (
+		keys    []string
+		names   []string
+		known   = map[string]int{}
+		objects = make([]map[string]json.RawMessage, len(records))
+		t       = &Table{}
+	)
+	for i, record := range records {
+		record = bytes.TrimSpace(record)
+		if len(record) == 0 || record[0] != '{' {
+			continue
+		}
+		fields, order, err := decodeObject(record)
+		if err != nil {
+			return nil, err
+		}
+		objects[i] = fields
+		for _, key := range order {
+			if _, ok := known[key]; !ok {
+				known[key] = len(keys)
+				keys = append(keys, escapeNewlines(key))
+				names = append(names, key)
+			}
+		}
+	}
+
+	// The header counts as line 0 so line based options can target it.
+	// Its source is the array of keys.
+	offset := 0
+	if len(keys) > 0 {
+		raw, _ := json.Marshal(names)
+		t.Rows = append(t.Rows, Row{Kind: RowHeader, Cells: keys, Raw: string(raw), Line: 0})
+		offset = 1
+	}
+
+	for i, record := range records {
+		var cells []string
+		switch {
+		case objects[i] != nil:
+			cells = make([]string, len(keys))
+			for key, value := range objects[i] {
+				cells[known[key]] = jsonCell(value)
+			}
+		case bytes.HasPrefix(bytes.TrimSpace(record), []byte("[")):
+			var values []json.RawMessage
+			if err := json.Unmarshal(record, &values); err != nil {
+				return nil, err
+			}
+			for _, value := range values {
+				cells = append(cells, jsonCell(value))
+			}
+		default:
+			cells = []string{jsonCell(record)}
+		}
+
+		var compact bytes.Buffer
+		json.Compact(&compact, record)
+		t.Rows = append(t.Rows, Row{Kind: RowData, Cells: cells, Raw: compact.String(), Line: i + offset, Record: i + offset})
+	}
+	return t, nil
+}
+
+// decodeObject decodes a JSON object and returns its fields along with
+// the keys in document order.
+func decodeObject(data []byte) (map[string]json.RawMessage, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	var order []string
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := token.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := fields[key]; !ok {
+			order = append(order, key)
+		}
+		fields[key] = value
+	}
+	return fields, order, nil
+}
+
+// jsonCell formats a JSON value as a cell: strings unquoted, null empty,
+// everything else as compact JSON.
+func jsonCell(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return escapeNewlines(s)
+	}
+	if string(bytes.TrimSpace(value)) == "null" {
+		return ""
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, value); err != nil {
+		return string(value)
+	}
+	return compact.String()
+}
+
+// newlineEscaper spells out line breaks, which would split a row.
+var newlineEscaper = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+// escapeNewlines writes the line breaks of a cell as \n, so the cell
+// stays on its row.
+func escapeNewlines(cell string) string {
+	return newlineEscaper.Replace(cell)
+}
+
+// FixedWidthParser reads columns laid out at fixed positions, such as
+// the output of ps or docker ps. Columns are split at gutters, the
+// positions blank on every line, so values wider than their header on
+// either side stay whole. A run between gutters under no text of the
+// first line, like the word after a single space in a value, belongs to
+// the column before it.
+type FixedWidthParser struct{}
+
+// Parse implements Parser.
+func (FixedWidthParser) Parse(input string) (*Table, error) {
+	input = strings.Trim(input, "\n")
+	if strings.TrimSpace(input) == "" {
+		return nil, ErrEmptyInput
+	}
+
+	lines := strings.Split(input, "\n")
+	var occupied []bool
+	for _, line := range lines {
+		for i := 0; i < len(line); i++ {
+			if i >= len(occupied) {
+				occupied = append(occupied, false)
+			}
+			if line[i] != ' ' && line[i] != '\t' {
+				occupied[i] = true
+			}
+		}
+	}
+
+	// bounds are the [start, end) positions of the columns
+	var bounds [][2]int
+	first := lines[0]
+	for i := 0; i < len(occupied); {
+		if !occupied[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(occupied) && occupied[i] {
+			i++
+		}
+		if len(bounds) > 0 && strings.TrimSpace(first[min(start, len(first)):min(i, len(first))]) == "" {
+			bounds[len(bounds)-1][1] = i
+			continue
+		}
+		bounds = append(bounds, [2]int{start, i})
+	}
+
+	return linesTable(lines, func(line string) ([]string, bool) {
+		var cells []string
+		for _, bound := range bounds {
+			if bound[0] >= len(line) {
+				break
+			}
+			cells = append(cells, strings.TrimSpace(line[bound[0]:min(bound[1], len(line))]))
+		}
+		for len(cells) > 0 && cells[len(cells)-1] == "" {
+			cells = cells[:len(cells)-1]
+		}
+		// Gutters are the delimiters: text in one column only has none
+		columns := 0
+		for _, cell := range cells {
+			if cell != "" {
+				columns++
+			}
+		}
+		return cells, columns > 1
+	}), nil
+}
@@ -0,0 +1,133 @@
+package vsf
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// cells returns the cells of every row of a table.
+func cells(t *Table) [][]string {
+	var out [][]string
+	for _, row := range t.Rows {
+		out = append(out, row.Cells)
+	}
+	return out
+}
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		parser    string
+		delimiter string
+		input     string
+		want      [][]string
+	}{
+		{
+			name:      "Delimiter",
+			parser:    "delim",
+			delimiter: ":",
+			input:     "name:'a:b'\nage:30",
+			want:      [][]string{{"name", "'a:b'"}, {"age", "30"}},
+		},
+		{
+			name:      "Regex",
+			parser:    "regex",
+			delimiter: `\s*[=:]\s*`,
+			input:     "name = john\nage: 30",
+			want:      [][]string{{"name", "john"}, {"age", "30"}},
+		},
+		{
+			name:   "Whitespace",
+			parser: "whitespace",
+			input:  "name   'john doe'\tx\nage 30",
+			want:   [][]string{{"name", "'john doe'", "x"}, {"age", "30"}},
+		},
+		{
+			name:      "CSV",
+			parser:    "csv",
+			delimiter: ",",
+			input:     "name,city\n\"doe, john\",\"new\nyork\"",
+			want:      [][]string{{"name", "city"}, {"doe, john", `new\nyork`}},
+		},
+		{
+			name:   "JSON objects",
+			parser: "json",
+			input:  `[{"a":1,"b":"x"},{"b":"y\nz","c":null,"d":[1,2]}]`,
+			want:   [][]string{{"a", "b", "c", "d"}, {"1", "x", "", ""}, {"", `y\nz`, "", "[1,2]"}},
+		},
+		{
+			name:   "JSON lines of arrays",
+			parser: "json",
+			input:  "[\"a\", 1]\n[\"b\", true]",
+			want:   [][]string{{"a", "1"}, {"b", "true"}},
+		},
+		{
+			name:   "Fixed width",
+			parser: "fixed",
+			input:  "  PID CMD        TIME\n    1 init       0:01\n  234 bash -l    0:00",
+			want:   [][]string{{"PID", "CMD", "TIME"}, {"1", "init", "0:01"}, {"234", "bash -l", "0:00"}},
+		},
+		{
+			name:   "Fixed width with data wider than the header",
+			parser: "fixed",
+			input:  "  PID TTY          TIME CMD\n98765 pts/0    00:00:00 ps\n    1 tty1     00:00:01 bash -l",
+			want: [][]string{
+				{"PID", "TTY", "TIME", "CMD"},
+				{"98765", "pts/0", "00:00:00", "ps"},
+				{"1", "tty1", "00:00:01", "bash -l"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(tt.parser, ParserOptions{Delimiter: tt.delimiter})
+			if err != nil {
+				t.Fatalf("NewParser() error = %v", err)
+			}
+			table, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := cells(table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsersEmptyInput(t *testing.T) {
+	for _, name := range []string{"delim", "regex", "whitespace", "csv", "json", "fixed"} {
+		p, _ := NewParser(name, ParserOptions{Delimiter: ","})
+		if _, err := p.Parse(" \n "); err != ErrEmptyInput {
+			t.Errorf("%s: Parse() error = %v, want ErrEmptyInput", name, err)
+		}
+	}
+}
+
+func TestJSONParserHeader(t *testing.T) {
+	table, _ := JSONParser{}.Parse(`{"a":1}` + "\n" + `{"a":2}`)
+	if table.Rows[0].Kind != RowHeader || table.Rows[0].Line != 0 || table.Rows[2].Line != 2 {
+		t.Errorf("Rows = %+v", table.Rows)
+	}
+	if got := table.Rows[0].Raw; got != `["a"]` {
+		t.Errorf("header Raw = %q, want %q", got, `["a"]`)
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	if _, err := NewParser("nope", ParserOptions{}); err == nil {
+		t.Error("NewParser() expected error for unknown parser")
+	}
+	if _, err := NewParser("regex", ParserOptions{Delimiter: "("}); err == nil {
+		t.Error("NewParser() expected error for invalid pattern")
+	}
+
+	RegisterParser("lines", func(ParserOptions) (Parser, error) {
+		return WhitespaceParser{}, nil
+	})
+	if !slices.Contains(Parsers(), "lines") {
+		t.Errorf("Parsers() = %v, want it to contain lines", Parsers())
+	}
+}
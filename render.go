@@ -0,0 +1,417 @@
+package vsf
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultRenderer is the name of the aligned text renderer.
+const DefaultRenderer = "text"
+
+// Align is the horizontal alignment of the cells of a column.
+type Align int
+
+const (
+	// AlignLeft pads cells on the right.
+	AlignLeft Align = iota
+	// AlignRight pads cells on the left.
+	AlignRight
+	// AlignCenter pads cells on both sides.
+	AlignCenter
+)
+
+// ParseAlign parses "left", "right" or "center", or their first letter.
+func ParseAlign(s string) (Align, error) {
+	switch s {
+	case "l", "left":
+		return AlignLeft, nil
+	case "r", "right":
+		return AlignRight, nil
+	case "c", "center":
+		return AlignCenter, nil
+	}
+	return 0, fmt.Errorf("invalid alignment: %s", s)
+}
+
+// Column is the computed layout of a single table column.
+type Column struct {
+	// Index is the 0-based position of the column.
+	Index int
+	// Name is the column title taken from the first header row, if any.
+	Name string
+	// Width is the max cell length of the column over data and header rows.
+	Width int
+	// Align is the alignment of the column's cells.
+	Align Align
+}
+
+// Columns computes the column metadata handed to renderers.
+func (t *Table) Columns() []Column {
+	widths := t.Widths()
+	cols := make([]Column, len(widths))
+	for i, width := range widths {
+		cols[i] = Column{Index: i, Width: width}
+	}
+
+	for _, row := range t.Rows {
+		if row.Kind != RowHeader {
+			continue
+		}
+		for i, cell := range row.Cells {
+			if i < len(cols) {
+				cols[i].Name = cell
+			}
+		}
+		break
+	}
+	return cols
+}
+
+// Renderer writes a table in some output format.
+type Renderer interface {
+	Render(w io.Writer, t *Table, cols []Column) error
+}
+
+// RenderOptions are the settings passed to a RendererFactory.
+type RenderOptions struct {
+	// Delimiter is written between cells by renderers that use one.
+	Delimiter string
+	// HeaderStyle decorates the cells of header rows. Nil leaves them as is.
+	HeaderStyle Style
+	// KeyDelimiter, when set, ends each hidden key field written before
+	// a row, see Row.Key.
+	KeyDelimiter string
+}
+
+// Style decorates the text of a cell. Padding is computed from the
+// undecorated text, so a style may add escape sequences freely.
+type Style func(string) string
+
+// styles are the header styles available to LookupStyle.
+var styles = map[string]Style{
+	"none":      nil,
+	"bold":      func(s string) string { return "\x1b[1m" + s + "\x1b[0m" },
+	"underline": func(s string) string { return "\x1b[4m" + s + "\x1b[0m" },
+	"upper":     strings.ToUpper,
+}
+
+// LookupStyle returns the style called name: none, bold, underline or upper.
+func LookupStyle(name string) (Style, error) {
+	style, ok := styles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown style: %s", name)
+	}
+	return style, nil
+}
+
+// RendererFactory creates a Renderer configured with opts.
+type RendererFactory func(opts RenderOptions) Renderer
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]RendererFactory{
+		"text": func(opts RenderOptions) Renderer {
+			return TextRenderer{Delimiter: opts.Delimiter, HeaderStyle: opts.HeaderStyle, KeyDelimiter: opts.KeyDelimiter}
+		},
+		"markdown": func(RenderOptions) Renderer {
+			return MarkdownRenderer{}
+		},
+		"expanded": func(opts RenderOptions) Renderer {
+			return ExpandedRenderer{Delimiter: opts.Delimiter, HeaderStyle: opts.HeaderStyle}
+		},
+	}
+)
+
+// RegisterRenderer makes a renderer available by name to NewRenderer
+// and the CLI -output flag. Registering an existing name replaces it.
+func RegisterRenderer(name string, factory RendererFactory) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = factory
+}
+
+// NewRenderer returns the renderer registered under name.
+func NewRenderer(name string, opts RenderOptions) (Renderer, error) {
+	renderersMu.RLock()
+	factory, ok := renderers[name]
+	renderersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer: %s", name)
+	}
+	return factory(opts), nil
+}
+
+// Renderers returns the sorted names of all registered renderers.
+func Renderers() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TextRenderer writes aligned text, one line per row, with cells joined
+// by Delimiter. The last cell of a row is not padded. This is the
+// default output of vsf. With KeyDelimiter set, every line starts with
+// the hidden key fields of its row, each followed by the delimiter, and
+// empty fields for rows without a key, so all lines split alike.
+type TextRenderer struct {
+	Delimiter    string
+	HeaderStyle  Style
+	KeyDelimiter string
+}
+
+// Render implements Renderer.
+func (r TextRenderer) Render(w io.Writer, t *Table, cols []Column) error {
+	keys := 0
+	if r.KeyDelimiter != "" {
+		for _, row := range t.Rows {
+			keys = max(keys, len(row.Key))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range t.Rows {
+		for i := range keys {
+			if i < len(row.Key) {
+				b.WriteString(row.Key[i])
+			}
+			b.WriteString(r.KeyDelimiter)
+		}
+
+		switch row.Kind {
+		case RowPassthrough:
+			b.WriteString(row.Raw)
+		case RowHeader:
+			if t.HeaderMode == HeaderAligned {
+				r.writeCells(&b, row, cols)
+			} else if r.HeaderStyle != nil {
+				b.WriteString(r.HeaderStyle(row.Raw))
+			} else {
+				b.WriteString(row.Raw)
+			}
+		case RowSeparator:
+			b.WriteString(row.Indent + separatorLine(row, cols, r.Delimiter))
+		case RowSection:
+			b.WriteString(sectionLine(row, t, cols, r.Delimiter))
+		default:
+			r.writeCells(&b, row, cols)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCells writes the cells of row padded to the column widths. The
+// last cell of a left aligned row gets no trailing padding.
+func (r TextRenderer) writeCells(b *strings.Builder, row Row, cols []Column) {
+	b.WriteString(row.Indent)
+	defer b.WriteString(row.Comment)
+
+	for col, cell := range row.Cells {
+		last := col == len(row.Cells)-1
+
+		var align Align
+		padding := 0
+		if col < len(cols) {
+			align = cols[col].Align
+			padding = max(cols[col].Width-len(cell), 0)
+		}
+
+		left := 0
+		switch align {
+		case AlignRight:
+			left = padding
+		case AlignCenter:
+			left = padding / 2
+		}
+
+		b.WriteString(strings.Repeat(" ", left))
+		if row.Kind == RowHeader && r.HeaderStyle != nil {
+			b.WriteString(r.HeaderStyle(cell))
+		} else {
+			b.WriteString(cell)
+		}
+		if !last {
+			b.WriteString(strings.Repeat(" ", padding-left))
+			b.WriteString(" " + r.Delimiter + " ")
+		}
+	}
+}
+
+// separatorLine draws a separator row so that it lines up with the
+// formatted row it mirrors: every column but the last spans its full
+// width, the last spans the mirrored cell. Without cells the separator
+// spans every column.
+func separatorLine(row Row, cols []Column, delimiter string) string {
+	spans := make([]int, 0, len(cols))
+	if len(row.Cells) == 0 {
+		for _, col := range cols {
+			spans = append(spans, col.Width)
+		}
+	} else {
+		for i, cell := range row.Cells {
+			if i < len(row.Cells)-1 && i < len(cols) {
+				spans = append(spans, cols[i].Width)
+			} else {
+				spans = append(spans, len(cell))
+			}
+		}
+	}
+
+	parts := make([]string, len(spans))
+	for i, span := range spans {
+		parts[i] = strings.Repeat(row.Sep, span)
+	}
+	return strings.Join(parts, row.Sep+delimiter+row.Sep)
+}
+
+// MarkdownRenderer writes a GitHub flavored Markdown table. The first
+// header row, or the first data row when there is none, becomes the
+// table header. Separator, passthrough and section rows are dropped.
+type MarkdownRenderer struct{}
+
+// Render implements Renderer.
+func (MarkdownRenderer) Render(w io.Writer, t *Table, cols []Column) error {
+	widths := make([]int, len(cols))
+	for i, col := range cols {
+		widths[i] = max(col.Width, 3)
+	}
+
+	var body []Row
+	header := -1
+	for i, row := range t.Rows {
+		if !row.aligned() {
+			continue
+		}
+		if header < 0 && row.Kind == RowHeader {
+			header = len(body)
+		}
+		body = append(body, t.Rows[i])
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	if header < 0 {
+		header = 0
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i, width := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = strings.ReplaceAll(cells[i], "|", `\|`)
+			}
+			b.WriteString(" " + cell + strings.Repeat(" ", max(width-len(cell), 0)) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(body[header].Cells)
+	b.WriteString("|")
+	for i, width := range widths {
+		rule := strings.Repeat("-", width)
+		switch cols[i].Align {
+		case AlignRight:
+			rule = rule[1:] + ":"
+		case AlignCenter:
+			rule = ":" + rule[2:] + ":"
+		}
+		b.WriteString(" " + rule + " |")
+	}
+	b.WriteString("\n")
+	for i, row := range body {
+		if i != header {
+			writeRow(row.Cells)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExpandedRenderer writes each data and footer row as a record of
+// aligned "name | value" lines under a "-[ RECORD n ]" title, like the
+// expanded display of psql, so wide tables read top to bottom. Names
+// come from the first header row, or are the 1-based column numbers.
+// Passthrough rows are written as is, section rows as their title, and
+// separators are dropped.
+type ExpandedRenderer struct {
+	Delimiter   string
+	HeaderStyle Style
+}
+
+// Render implements Renderer.
+func (r ExpandedRenderer) Render(w io.Writer, t *Table, cols []Column) error {
+	name := func(i int) string {
+		if i < len(cols) {
+			return cmp.Or(cols[i].Name, strconv.Itoa(i+1))
+		}
+		return strconv.Itoa(i + 1)
+	}
+
+	// The names and values of every record make a two column table
+	// whose widths are shared by all records, zero without any.
+	var pairs [][]string
+	for _, row := range t.Rows {
+		if row.Kind == RowData || row.Kind == RowFooter {
+			for i, cell := range row.Cells {
+				pairs = append(pairs, []string{name(i), cell})
+			}
+		}
+	}
+	widths := append(computeMaxLengths(pairs), 0, 0)
+	titleWidth := widths[0] + widths[1] + utf8.RuneCountInString(r.Delimiter) + 2
+
+	var b strings.Builder
+	record := 0
+	for _, row := range t.Rows {
+		switch row.Kind {
+		case RowPassthrough:
+			b.WriteString(row.Raw + "\n")
+			continue
+		case RowSection:
+			b.WriteString(strings.TrimSpace(row.Raw) + "\n")
+			continue
+		case RowData:
+			record++
+			title := fmt.Sprintf("-[ RECORD %d ]", record)
+			b.WriteString(title + strings.Repeat("-", max(titleWidth-len(title), 0)) + "\n")
+		case RowFooter:
+			title := "-[ FOOTER ]"
+			b.WriteString(title + strings.Repeat("-", max(titleWidth-len(title), 0)) + "\n")
+		default:
+			continue
+		}
+
+		for i, cell := range row.Cells {
+			key := name(i)
+			padding := strings.Repeat(" ", widths[0]-len(key))
+			if r.HeaderStyle != nil {
+				key = r.HeaderStyle(key)
+			}
+			b.WriteString(key + padding + " " + r.Delimiter)
+			if cell != "" {
+				b.WriteString(" " + cell)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,106 @@
+package vsf
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTextRenderer(t *testing.T) {
+	table, _ := ParseTable("name:john\nage:30", ":")
+
+	var b strings.Builder
+	if err := (TextRenderer{Delimiter: "|"}).Render(&b, table, table.Columns()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := b.String(), "name | john\nage  | 30\n"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	table, _ := ParseTable("name:city\njohnny:new|york\nx\namy:rome", ":")
+	table.Skip(2)
+	table.InsertSeparator(0, "-")
+
+	var b strings.Builder
+	if err := (MarkdownRenderer{}).Render(&b, table, table.Columns()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "| name   | city     |\n" +
+		"| ------ | -------- |\n" +
+		"| johnny | new\\|york |\n" +
+		"| amy    | rome     |\n"
+	if got := b.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestExpandedRenderer(t *testing.T) {
+	table, _ := ParseTable("name:city\n# europe\njohnny:\namy:rome:it", ":")
+	table.MarkHeader(1)
+	table.Skip(1)
+
+	var b strings.Builder
+	if err := (ExpandedRenderer{Delimiter: "|"}).Render(&b, table, table.Columns()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "# europe\n" +
+		"-[ RECORD 1 ]\n" +
+		"name | johnny\n" +
+		"-[ RECORD 2 ]\n" +
+		"name | amy\n" +
+		"city | rome\n" +
+		"3    | it\n"
+	if got := b.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+// countRenderer is a minimal custom renderer used to test the registry.
+type countRenderer struct{ prefix string }
+
+func (r countRenderer) Render(w io.Writer, t *Table, cols []Column) error {
+	_, err := fmt.Fprintf(w, "%s%d rows, %d columns", r.prefix, len(t.Rows), len(cols))
+	return err
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	RegisterRenderer("count", func(opts RenderOptions) Renderer {
+		return countRenderer{prefix: opts.Delimiter}
+	})
+
+	if !slices.Contains(Renderers(), "count") {
+		t.Fatalf("Renderers() = %v, want it to contain count", Renderers())
+	}
+
+	r, err := NewRenderer("count", RenderOptions{Delimiter: "> "})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	table, _ := ParseTable("a:b:c\nd:e", ":")
+	var b strings.Builder
+	r.Render(&b, table, table.Columns())
+	if got, want := b.String(), "> 2 rows, 3 columns"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	if _, err := NewRenderer("nope", RenderOptions{}); err == nil {
+		t.Error("NewRenderer() expected error for unknown renderer")
+	}
+}
+
+func TestColumnsName(t *testing.T) {
+	table, _ := ParseTable("id:name\n1:john", ":")
+	table.Rows[0].Kind = RowHeader
+
+	cols := table.Columns()
+	if cols[0].Name != "id" || cols[1].Name != "name" || cols[1].Width != 4 {
+		t.Errorf("Columns() = %+v", cols)
+	}
+}
@@ -0,0 +1,91 @@
+package vsf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultSectionChar frames section rows when no character is set.
+const DefaultSectionChar = "─"
+
+// SectionStyle controls how section rows are laid out across the table.
+type SectionStyle int
+
+const (
+	// SectionCenter centers the title over the table.
+	SectionCenter SectionStyle = iota
+	// SectionLeft writes the title at the left edge of the table.
+	SectionLeft
+	// SectionFramed centers the title between rules spanning the table.
+	SectionFramed
+)
+
+// String returns the name accepted by ParseSectionStyle.
+func (s SectionStyle) String() string {
+	switch s {
+	case SectionCenter:
+		return "center"
+	case SectionLeft:
+		return "left"
+	case SectionFramed:
+		return "framed"
+	}
+	return fmt.Sprintf("SectionStyle(%d)", int(s))
+}
+
+// ParseSectionStyle parses "center", "left" or "framed".
+func ParseSectionStyle(s string) (SectionStyle, error) {
+	for _, style := range []SectionStyle{SectionCenter, SectionLeft, SectionFramed} {
+		if s == style.String() {
+			return style, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid section style: %s", s)
+}
+
+// SectionMatch marks the rows whose source line matches re as sections.
+// Like passthrough rows, they don't affect column widths.
+func (t *Table) SectionMatch(re *regexp.Regexp) {
+	for i := range t.Rows {
+		if t.Rows[i].Line >= 0 && re.MatchString(t.Rows[i].Raw) {
+			t.Rows[i].Kind = RowSection
+		}
+	}
+}
+
+// sectionLine lays out the title of a section row over the full width
+// of the formatted table.
+func sectionLine(row Row, t *Table, cols []Column, delimiter string) string {
+	title := strings.TrimSpace(row.Raw)
+	width := tableWidth(cols, delimiter)
+
+	switch t.SectionStyle {
+	case SectionLeft:
+		return title
+	case SectionFramed:
+		char := t.SectionChar
+		if char == "" {
+			char = DefaultSectionChar
+		}
+		fill := width - len(title) - 2
+		left := max(fill/2, 1)
+		right := max(fill-left, 1)
+		return strings.Repeat(char, left) + " " + title + " " + strings.Repeat(char, right)
+	}
+	return strings.Repeat(" ", max((width-len(title))/2, 0)) + title
+}
+
+// tableWidth returns the width of a fully padded row: every column plus
+// the delimiters between them.
+func tableWidth(cols []Column, delimiter string) int {
+	if len(cols) == 0 {
+		return 0
+	}
+	width := (len(cols) - 1) * (utf8.RuneCountInString(delimiter) + 2)
+	for _, col := range cols {
+		width += col.Width
+	}
+	return width
+}
@@ -0,0 +1,66 @@
+package vsf
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSectionStyles(t *testing.T) {
+	const input = "name:age\nPEOPLE\njohn:30\namy:25"
+
+	tests := []struct {
+		name  string
+		style SectionStyle
+		char  string
+		want  string
+	}{
+		{
+			name:  "Center",
+			style: SectionCenter,
+			want:  "name     | age\n    PEOPLE\njohn     | 30\namy      | 25",
+		},
+		{
+			name:  "Left",
+			style: SectionLeft,
+			want:  "name     | age\nPEOPLE\njohn     | 30\namy      | 25",
+		},
+		{
+			name:  "Framed",
+			style: SectionFramed,
+			char:  "=",
+			want:  "name     | age\n=== PEOPLE ===\njohn     | 30\namy      | 25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(
+				WithOutputDelimiter("|"),
+				WithSectionMatch(regexp.MustCompile(`^[A-Z]+$`)),
+				WithSectionStyle(tt.style, tt.char),
+				WithMinWidth(0, 8),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := f.Format(input)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionUndelimited(t *testing.T) {
+	table, _ := ParseTable("REPORT\nname:john\nage:30", ":")
+	table.SectionUndelimited()
+	table.SectionStyle = SectionFramed
+
+	want := "─ REPORT ──\nname | john\nage  | 30"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package vsf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Separator is a rule placing a separator line next to the lines it
+// targets.
+type Separator struct {
+	// Lines are the source lines to target. Ignored when Match is set.
+	Lines LineSpec
+	// Match, when set, targets every source line it matches.
+	Match *regexp.Regexp
+	// Before draws the separator above the target instead of below it.
+	Before bool
+	// Char is repeated to draw the separator.
+	Char string
+}
+
+// ParseSeparator parses a separator rule of the form
+//
+//	[after:|before:]TARGET[:CHAR]
+//
+// where TARGET is a line spec as accepted by ParseLineSpec, or a
+// /regular expression/ matched against each line. CHAR defaults to
+// defaultChar.
+//
+// Examples:
+//
+//	ParseSeparator("0", "═")              // after the first line
+//	ParseSeparator("before:-1:-", "═")    // above the last line, drawn with "-"
+//	ParseSeparator("after:/^total/", "═") // below every line starting with "total"
+//	ParseSeparator("2-4:·", "═")          // below lines 2, 3 and 4
+func ParseSeparator(spec, defaultChar string) (Separator, error) {
+	sep := Separator{Char: defaultChar}
+
+	rest := spec
+	if after, ok := strings.CutPrefix(rest, "after:"); ok {
+		rest = after
+	} else if before, ok := strings.CutPrefix(rest, "before:"); ok {
+		rest, sep.Before = before, true
+	}
+
+	var target string
+	if strings.HasPrefix(rest, "/") {
+		end := -1
+		for i := len(rest) - 1; i > 0; i-- {
+			if rest[i] == '/' && (i == len(rest)-1 || rest[i+1] == ':') {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return sep, fmt.Errorf("invalid separator %q: unterminated pattern", spec)
+		}
+
+		re, err := regexp.Compile(rest[1:end])
+		if err != nil {
+			return sep, fmt.Errorf("invalid separator %q: %w", spec, err)
+		}
+		sep.Match = re
+		rest = rest[end+1:]
+	} else {
+		target, rest, _ = strings.Cut(rest, ":")
+		lines, err := ParseLineSpec(target)
+		if err != nil || len(lines) == 0 {
+			return sep, fmt.Errorf("invalid separator %q: bad line spec", spec)
+		}
+		sep.Lines = lines
+		rest = ":" + rest
+	}
+
+	if char := strings.TrimPrefix(rest, ":"); char != "" {
+		sep.Char = char
+	}
+	return sep, nil
+}
+
+// AddSeparators adds a separator row for each line targeted by each rule.
+// Separators mirror the row they are attached to, so they span the same
+// columns. Several separators on the same side of a row keep the order of
+// the rules.
+func (t *Table) AddSeparators(seps ...Separator) {
+	if len(seps) == 0 {
+		return
+	}
+
+	lines := t.LineCount()
+	before := make(map[int][]Row)
+	after := make(map[int][]Row)
+	for _, sep := range seps {
+		for i, row := range t.Rows {
+			if row.Line < 0 || !sep.targets(row, lines) {
+				continue
+			}
+
+			rule := Row{Kind: RowSeparator, Line: -1, Sep: sep.Char, Indent: row.Indent}
+			if row.aligned() {
+				rule.Cells = row.Cells
+			}
+			if sep.Before {
+				before[i] = append(before[i], rule)
+			} else {
+				after[i] = append(after[i], rule)
+			}
+		}
+	}
+
+	rows := make([]Row, 0, len(t.Rows)+len(before)+len(after))
+	for i, row := range t.Rows {
+		rows = append(rows, before[i]...)
+		rows = append(rows, row)
+		rows = append(rows, after[i]...)
+	}
+	t.Rows = rows
+}
+
+// targets reports whether the separator applies to row in a table of
+// lines source lines.
+func (s Separator) targets(row Row, lines int) bool {
+	if s.Match != nil {
+		return s.Match.MatchString(row.Raw)
+	}
+
+	return s.Lines.Contains(row.Line, lines)
+}
@@ -0,0 +1,98 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSeparator(t *testing.T) {
+	tests := []struct {
+		spec    string
+		lines   LineSpec
+		match   string
+		before  bool
+		char    string
+		wantErr bool
+	}{
+		{spec: "0", lines: Lines(0), char: "="},
+		{spec: "after:3:-", lines: Lines(3), char: "-"},
+		{spec: "before:-1", lines: Lines(-1), before: true, char: "="},
+		{spec: "2-4,-1:·", lines: LineSpec{{From: 2, To: 4}, {From: -1, To: -1}}, char: "·"},
+		{spec: "/^total/", match: "^total", char: "="},
+		{spec: "before:/a:b/:~", match: "a:b", before: true, char: "~"},
+		{spec: "/a/b/", match: "a/b", char: "="},
+		{spec: "x", wantErr: true},
+		{spec: "/unterminated", wantErr: true},
+		{spec: "/(/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSeparator(tt.spec, "=")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeparator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			match := ""
+			if got.Match != nil {
+				match = got.Match.String()
+			}
+			if !reflect.DeepEqual(got.Lines, tt.lines) || match != tt.match || got.Before != tt.before || got.Char != tt.char {
+				t.Errorf("ParseSeparator() = {Lines: %v, Match: %q, Before: %v, Char: %q}", got.Lines, match, got.Before, got.Char)
+			}
+		})
+	}
+}
+
+func TestAddSeparators(t *testing.T) {
+	const input = "name:amount\nweb:10\nweb:5\ndb:7\ntotal:22"
+
+	tests := []struct {
+		name  string
+		specs []string
+		want  string
+	}{
+		{
+			name:  "Header and totals",
+			specs: []string{"0:=", "before:-1:-"},
+			want:  "name  | amount\n======|=======\nweb   | 10\nweb   | 5\ndb    | 7\n------|---\ntotal | 22",
+		},
+		{
+			name:  "Pattern",
+			specs: []string{"/^web/:."},
+			want:  "name  | amount\nweb   | 10\n......|...\nweb   | 5\n......|..\ndb    | 7\ntotal | 22",
+		},
+		{
+			name:  "Same line keeps rule order",
+			specs: []string{"-1:=", "-1:-"},
+			want:  "name  | amount\nweb   | 10\nweb   | 5\ndb    | 7\ntotal | 22\n======|===\n------|---",
+		},
+		{
+			name:  "Out of range",
+			specs: []string{"9", "-9"},
+			want:  "name  | amount\nweb   | 10\nweb   | 5\ndb    | 7\ntotal | 22",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seps []Separator
+			for _, spec := range tt.specs {
+				sep, err := ParseSeparator(spec, "=")
+				if err != nil {
+					t.Fatalf("ParseSeparator() error = %v", err)
+				}
+				seps = append(seps, sep)
+			}
+
+			table, _ := ParseTable(input, ":")
+			table.AddSeparators(seps...)
+			if got := renderText(table, "|"); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
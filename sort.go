@@ -0,0 +1,222 @@
+package vsf
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SortType selects how the cells of a sort key are compared.
+type SortType int
+
+const (
+	// SortString compares cells as strings.
+	SortString SortType = iota
+	// SortNumeric compares cells as numbers, see parseNumber.
+	SortNumeric
+	// SortNatural compares runs of digits as numbers, so "v1.10" sorts
+	// after "v1.9".
+	SortNatural
+	// SortSize compares human readable sizes like "512", "1.5K" or "2GiB".
+	SortSize
+	// SortDate compares dates and times, see dateLayouts.
+	SortDate
+)
+
+// sortTypes maps the letters of a sort key spec to their type.
+var sortTypes = map[string]SortType{
+	"s": SortString,
+	"n": SortNumeric,
+	"v": SortNatural,
+	"h": SortSize,
+	"d": SortDate,
+}
+
+// SortKey is a column to sort rows by.
+type SortKey struct {
+	Column ColumnRef
+	Type   SortType
+	Desc   bool
+}
+
+// ParseSortKeys parses comma-separated sort keys. Each key is a 1-based
+// column number or a header name, followed by optional ":"-separated
+// modifiers: a type (s, n, v, h or d) and an order (asc or desc).
+//
+// Example:
+//
+//	ParseSortKeys("2:n:desc,name")
+//	// [{Column: {Index: 1}, Type: SortNumeric, Desc: true}, {Column: {Name: "name"}}]
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ":")
+		column, err := ParseColumnRef(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort key %q: %w", part, err)
+		}
+
+		key := SortKey{Column: column}
+		for _, modifier := range fields[1:] {
+			switch modifier {
+			case "asc":
+				key.Desc = false
+			case "desc":
+				key.Desc = true
+			default:
+				typ, ok := sortTypes[modifier]
+				if !ok {
+					return nil, fmt.Errorf("invalid sort key %q: unknown modifier %s", part, modifier)
+				}
+				key.Type = typ
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sort orders the data rows by keys, comparing each key in turn. The
+// sort is stable and only data rows move: headers, passthrough lines
+// and every other row keep their place.
+func (t *Table) Sort(keys ...SortKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	columns := make([]int, len(keys))
+	for i, key := range keys {
+		column, err := key.Column.resolve(t)
+		if err != nil {
+			return err
+		}
+		columns[i] = column
+	}
+
+	var (
+		slots []int
+		rows  []Row
+	)
+	for i, row := range t.Rows {
+		if row.Kind == RowData {
+			slots = append(slots, i)
+			rows = append(rows, row)
+		}
+	}
+
+	slices.SortStableFunc(rows, func(a, b Row) int {
+		for i, key := range keys {
+			c := compareCells(cellAt(a, columns[i]), cellAt(b, columns[i]), key.Type)
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	for i, slot := range slots {
+		t.Rows[slot] = rows[i]
+	}
+	return nil
+}
+
+// compareCells compares two cells as typ. In ascending order, cells that
+// don't parse as typ sort after those that do, and compare as strings
+// among themselves.
+func compareCells(a, b string, typ SortType) int {
+	var parse func(string) (float64, bool)
+	switch typ {
+	case SortNatural:
+		return compareNatural(a, b)
+	case SortNumeric:
+		parse = parseNumber
+	case SortSize:
+		parse = parseSize
+	case SortDate:
+		parse = func(s string) (float64, bool) {
+			d, ok := parseDate(s)
+			return float64(d.UnixNano()), ok
+		}
+	default:
+		return strings.Compare(a, b)
+	}
+
+	x, okA := parse(a)
+	y, okB := parse(b)
+	switch {
+	case okA && okB:
+		return cmp.Compare(x, y)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// sizeUnits are the powers of 1024 of the size suffixes.
+const sizeUnits = "KMGTPE"
+
+// parseSize parses a human readable size: a number with an optional
+// K, M, G, T, P or E suffix, in any case and optionally followed by
+// "i" and "B", counted in powers of 1024.
+func parseSize(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	cell = strings.TrimSuffix(strings.TrimSuffix(cell, "B"), "i")
+
+	multiplier := 1.0
+	if cell != "" {
+		if unit := strings.Index(sizeUnits, strings.ToUpper(cell[len(cell)-1:])); unit >= 0 {
+			for range unit + 1 {
+				multiplier *= 1024
+			}
+			cell = cell[:len(cell)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	return n * multiplier, err == nil
+}
+
+// compareNatural compares strings chunk by chunk, runs of digits by
+// their numeric value and everything else as strings.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		chunkA, restA := naturalChunk(a)
+		chunkB, restB := naturalChunk(b)
+
+		var c int
+		if isDigit(chunkA[0]) && isDigit(chunkB[0]) {
+			numA := strings.TrimLeft(chunkA, "0")
+			numB := strings.TrimLeft(chunkB, "0")
+			c = cmp.Or(cmp.Compare(len(numA), len(numB)), strings.Compare(numA, numB))
+		} else {
+			c = strings.Compare(chunkA, chunkB)
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// naturalChunk splits s after its leading run of digits or non-digits.
+func naturalChunk(s string) (string, string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,69 @@
+package vsf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	got, err := ParseSortKeys("2:n:desc,name,3:h")
+	if err != nil {
+		t.Fatalf("ParseSortKeys() error = %v", err)
+	}
+	want := []SortKey{
+		{Column: ColumnRef{Index: 1}, Type: SortNumeric, Desc: true},
+		{Column: ColumnRef{Name: "name"}},
+		{Column: ColumnRef{Index: 2}, Type: SortSize},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSortKeys() = %+v, want %+v", got, want)
+	}
+
+	for _, spec := range []string{"0", "1:x", ""} {
+		if _, err := ParseSortKeys(spec); err == nil {
+			t.Errorf("ParseSortKeys(%q) expected error", spec)
+		}
+	}
+}
+
+func TestCompareCells(t *testing.T) {
+	tests := []struct {
+		a, b string
+		typ  SortType
+		want int
+	}{
+		{a: "10", b: "9", typ: SortString, want: -1},
+		{a: "10", b: "9", typ: SortNumeric, want: 1},
+		{a: "1,000", b: "999", typ: SortNumeric, want: 1},
+		{a: "n/a", b: "999", typ: SortNumeric, want: 1},
+		{a: "v1.10.0", b: "v1.9.2", typ: SortNatural, want: 1},
+		{a: "file02", b: "file2", typ: SortNatural, want: 0},
+		{a: "1.5K", b: "1023", typ: SortSize, want: 1},
+		{a: "2GiB", b: "3M", typ: SortSize, want: 1},
+		{a: "2024-01-02", b: "2023-12-31", typ: SortDate, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareCells(tt.a, tt.b, tt.typ); got != tt.want {
+			t.Errorf("compareCells(%q, %q, %v) = %d, want %d", tt.a, tt.b, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestTableSort(t *testing.T) {
+	table, _ := ParseTable("name:age\n# staff\nbob:30\namy:25\ncarl:30\ndan:25", ":")
+	table.MarkHeader(1)
+	table.Skip(1)
+
+	if err := table.Sort(SortKey{Column: ColumnRef{Name: "age"}, Type: SortNumeric, Desc: true}); err != nil {
+		t.Fatalf("Sort() error = %v", err)
+	}
+	want := [][]string{{"name", "age"}, {"# staff"}, {"bob", "30"}, {"carl", "30"}, {"amy", "25"}, {"dan", "25"}}
+	if got := cells(table); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() = %q, want %q", got, want)
+	}
+
+	if err := table.Sort(SortKey{Column: ColumnRef{Name: "city"}}); err == nil {
+		t.Error("Sort() expected error for unknown column")
+	}
+}
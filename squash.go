@@ -0,0 +1,44 @@
+package vsf
+
+import "slices"
+
+// Squash blanks out the cells of columns equal to the cell above, so
+// rows sharing keys read like a tree. Columns are squashed in order, a
+// column only when all the columns before it were, and runs restart
+// after any row that isn't data. Squashed cells become ditto, which may
+// be empty or a mark like `"`.
+func (t *Table) Squash(ditto string, columns ...ColumnRef) error {
+	indexes := make([]int, len(columns))
+	for i, ref := range columns {
+		column, err := ref.resolve(t)
+		if err != nil {
+			return err
+		}
+		indexes[i] = column
+	}
+	slices.Sort(indexes)
+
+	var above []string
+	for i, row := range t.Rows {
+		if row.Kind != RowData {
+			above = nil
+			continue
+		}
+
+		var squashed []string
+		for _, column := range indexes {
+			if column >= len(row.Cells) || column >= len(above) || row.Cells[column] != above[column] {
+				break
+			}
+			if squashed == nil {
+				squashed = slices.Clone(row.Cells)
+			}
+			squashed[column] = ditto
+		}
+		above = row.Cells
+		if squashed != nil {
+			t.Rows[i].Cells = squashed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package vsf
+
+import "testing"
+
+func TestTableSquash(t *testing.T) {
+	const input = "main:fix:alice\nmain:fix:bob\nmain:docs:bob\n# release\nmain:tag:carol\ndev:fix:alice"
+
+	tests := []struct {
+		name    string
+		ditto   string
+		columns []ColumnRef
+		want    string
+	}{
+		{
+			name:    "One column",
+			columns: []ColumnRef{{Index: 0}},
+			want: "main | fix  | alice\n" +
+				"     | fix  | bob\n" +
+				"     | docs | bob\n" +
+				"# release\n" +
+				"main | tag  | carol\n" +
+				"dev  | fix  | alice",
+		},
+		{
+			name:    "Nested columns with a ditto mark",
+			ditto:   `"`,
+			columns: []ColumnRef{{Index: 1}, {Index: 0}},
+			want: "main | fix  | alice\n" +
+				`"    | "    | bob` + "\n" +
+				`"    | docs | bob` + "\n" +
+				"# release\n" +
+				"main | tag  | carol\n" +
+				"dev  | fix  | alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := ParseTable(input, ":")
+			table.PassUndelimited()
+			if err := table.Squash(tt.ditto, tt.columns...); err != nil {
+				t.Fatalf("Squash() error = %v", err)
+			}
+			if got := renderText(table, "|"); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterHiddenKey(t *testing.T) {
+	f, _ := New(
+		WithOutputDelimiter("|"),
+		WithSort(SortKey{Column: ColumnRef{Index: 0}}),
+		WithSquash("", ColumnRef{Index: 0}),
+		WithHiddenKey(),
+	)
+	got, err := f.Format("b:1\na:2\nb:3")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "a:2\x1fa | 2\nb:1\x1fb | 1\nb:3\x1f  | 3"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
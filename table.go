@@ -0,0 +1,233 @@
+package vsf
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RowKind describes how a row takes part in width calculation and rendering.
+type RowKind int
+
+const (
+	// RowData is a regular row. Its cells are aligned and count towards
+	// the column widths.
+	RowData RowKind = iota
+	// RowHeader holds column titles. It is aligned like a data row.
+	RowHeader
+	// RowSeparator is a rule drawn across the columns of the row above it.
+	RowSeparator
+	// RowPassthrough is written verbatim and ignored for column widths.
+	RowPassthrough
+	// RowSection is a title spanning the whole table, laid out from the
+	// column widths. Like a passthrough row it doesn't affect them.
+	RowSection
+	// RowFooter holds generated values, such as totals, below the data.
+	// It is aligned like a data row.
+	RowFooter
+)
+
+// String returns a lowercase name for the row kind.
+func (k RowKind) String() string {
+	switch k {
+	case RowData:
+		return "data"
+	case RowHeader:
+		return "header"
+	case RowSeparator:
+		return "separator"
+	case RowPassthrough:
+		return "passthrough"
+	case RowSection:
+		return "section"
+	case RowFooter:
+		return "footer"
+	}
+	return fmt.Sprintf("RowKind(%d)", int(k))
+}
+
+// Row is a single line of a Table.
+type Row struct {
+	Kind RowKind
+	// Cells are the parsed columns. For a separator row they mirror the
+	// cells of the row it follows, so the rule spans the same columns.
+	Cells []string
+	// Raw is the original input line, used for passthrough and section rows.
+	Raw string
+	// Line is the 0-based index of the source line, or -1 for rows that
+	// were generated rather than parsed.
+	Line int
+	// Record is the 0-based index of the row among the rows the parser
+	// produced. It differs from Line when a record spans several lines.
+	Record int
+	// Sep is the character repeated to draw a separator row.
+	Sep string
+	// Indent is written before the cells, keeping the indentation of
+	// source code. See CodeParser.
+	Indent string
+	// Comment is written after the last cell, keeping a trailing comment
+	// in place.
+	Comment string
+	// Undelimited reports that the parser found no delimiter in the line,
+	// so it has no columns to line up. See PassUndelimited.
+	Undelimited bool
+	// Key are hidden fields written before the row by renderers with a
+	// key delimiter, such as the source line of a squashed row.
+	Key []string
+}
+
+// aligned reports whether the row's cells are padded to the column widths.
+func (r Row) aligned() bool {
+	return r.Kind == RowData || r.Kind == RowHeader || r.Kind == RowFooter
+}
+
+// Table is the structured form of the input that parsers produce and
+// renderers consume. Separators and borders are generated from the
+// column widths instead of by reparsing formatted text.
+type Table struct {
+	Rows []Row
+	// HeaderMode controls how the header rows are laid out.
+	HeaderMode HeaderMode
+	// SectionStyle controls how section rows are laid out.
+	SectionStyle SectionStyle
+	// SectionChar frames section rows with SectionFramed. Empty uses
+	// DefaultSectionChar.
+	SectionChar string
+}
+
+// ParseTable splits input into lines and each line into cells using
+// ParseLine. Every row starts out as RowData. It is a shorthand for
+// DelimiterParser{Delimiter: delimiter}.Parse(input).
+//
+// Example:
+//
+//	t, _ := ParseTable("name:john\nage:30", ":")
+//	// t.Rows[0].Cells = ["name", "john"]
+//	// t.Widths()      = [4, 4]
+func ParseTable(input, delimiter string) (*Table, error) {
+	return DelimiterParser{Delimiter: delimiter}.Parse(input)
+}
+
+// Widths returns the max cell length of each column, computed from the
+// data and footer rows and, unless HeaderMode is HeaderExcluded, the
+// header rows.
+func (t *Table) Widths() []int {
+	var rows [][]string
+	for _, row := range t.Rows {
+		if row.Kind == RowData || row.Kind == RowFooter || (row.Kind == RowHeader && t.HeaderMode != HeaderExcluded) {
+			rows = append(rows, row.Cells)
+		}
+	}
+	return computeMaxLengths(rows)
+}
+
+// LineCount returns the number of source lines the table was parsed from.
+func (t *Table) LineCount() int {
+	n := 0
+	for _, row := range t.Rows {
+		n = max(n, row.Line+1)
+	}
+	return n
+}
+
+// Skip marks the rows parsed from the given source lines as passthrough,
+// so they are written as-is and don't affect column widths. Line numbers
+// out of range are ignored.
+func (t *Table) Skip(lines ...int) {
+	skip := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		skip[line] = true
+	}
+	for i := range t.Rows {
+		if t.Rows[i].Line >= 0 && skip[t.Rows[i].Line] {
+			t.Rows[i].Kind = RowPassthrough
+		}
+	}
+}
+
+// SkipMatch marks the rows whose source line matches re as passthrough,
+// so they are written as-is and don't affect column widths.
+func (t *Table) SkipMatch(re *regexp.Regexp) {
+	for i := range t.Rows {
+		if t.Rows[i].Line >= 0 && re.MatchString(t.Rows[i].Raw) {
+			t.Rows[i].Kind = RowPassthrough
+		}
+	}
+}
+
+// UndelimitedMode controls how lines without the delimiter, parsed into
+// a single cell, are laid out.
+type UndelimitedMode int
+
+const (
+	// UndelimitedPassthrough writes them verbatim and leaves them out of
+	// the column widths, so a title line can't push every row right.
+	UndelimitedPassthrough UndelimitedMode = iota
+	// UndelimitedAligned keeps them as one-column rows.
+	UndelimitedAligned
+	// UndelimitedSection turns them into section rows spanning the table.
+	UndelimitedSection
+)
+
+// String returns the name accepted by ParseUndelimitedMode.
+func (m UndelimitedMode) String() string {
+	switch m {
+	case UndelimitedPassthrough:
+		return "passthrough"
+	case UndelimitedAligned:
+		return "aligned"
+	case UndelimitedSection:
+		return "section"
+	}
+	return fmt.Sprintf("UndelimitedMode(%d)", int(m))
+}
+
+// ParseUndelimitedMode parses "passthrough", "aligned" or "section".
+func ParseUndelimitedMode(s string) (UndelimitedMode, error) {
+	for _, mode := range []UndelimitedMode{UndelimitedPassthrough, UndelimitedAligned, UndelimitedSection} {
+		if s == mode.String() {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid undelimited mode: %s", s)
+}
+
+// PassUndelimited marks undelimited data rows as passthrough, see
+// Row.Undelimited. Tables where no row has a delimiter are left alone,
+// as there is nothing to align them with.
+func (t *Table) PassUndelimited() {
+	t.markUndelimited(RowPassthrough)
+}
+
+// SectionUndelimited marks undelimited data rows as sections, with the
+// same rules as PassUndelimited.
+func (t *Table) SectionUndelimited() {
+	t.markUndelimited(RowSection)
+}
+
+// markUndelimited sets the kind of undelimited data rows.
+func (t *Table) markUndelimited(kind RowKind) {
+	split := false
+	for _, row := range t.Rows {
+		split = split || (row.Kind == RowData && !row.Undelimited)
+	}
+	if !split {
+		return
+	}
+
+	for i, row := range t.Rows {
+		if row.Kind == RowData && row.Undelimited {
+			t.Rows[i].Kind = kind
+		}
+	}
+}
+
+// InsertSeparator adds a separator row drawn with sepChar after the row
+// parsed from source line afterLine. Out of range line numbers, negative
+// ones included, leave the table unchanged. See AddSeparators for more
+// placement rules.
+func (t *Table) InsertSeparator(afterLine int, sepChar string) {
+	if afterLine < 0 {
+		return
+	}
+	t.AddSeparators(Separator{Lines: Lines(afterLine), Char: sepChar})
+}
@@ -0,0 +1,113 @@
+package vsf
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// renderText renders a table with the text renderer, without the
+// trailing newline.
+func renderText(t *Table, delimiter string) string {
+	var b strings.Builder
+	TextRenderer{Delimiter: delimiter}.Render(&b, t, t.Columns())
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func TestParseTable(t *testing.T) {
+	table, err := ParseTable("name:john\nage:30\ncity:new york", ":")
+	if err != nil {
+		t.Fatalf("ParseTable() error = %v", err)
+	}
+
+	if len(table.Rows) != 3 {
+		t.Fatalf("ParseTable() rows = %d, want 3", len(table.Rows))
+	}
+	for i, row := range table.Rows {
+		if row.Kind != RowData || row.Line != i {
+			t.Errorf("row %d = {Kind: %v, Line: %d}, want {Kind: data, Line: %d}", i, row.Kind, row.Line, i)
+		}
+	}
+	if got, want := table.Widths(), []int{4, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Widths() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseTable(" \n ", ":"); err == nil {
+		t.Error("ParseTable() expected error for empty input")
+	}
+}
+
+func TestTableSkip(t *testing.T) {
+	table, _ := ParseTable("VERY_LONG_HEADER:x\nshort:val", ":")
+	table.Skip(0, 7)
+
+	if table.Rows[0].Kind != RowPassthrough {
+		t.Errorf("Rows[0].Kind = %v, want passthrough", table.Rows[0].Kind)
+	}
+	if got, want := table.Widths(), []int{5, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Widths() = %v, want %v", got, want)
+	}
+}
+
+func TestTableInsertSeparator(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		skip      []int
+		afterLine int
+		want      string
+	}{
+		{
+			name:      "Cell containing the output delimiter",
+			input:     "a | b:header\nlong value:x",
+			afterLine: 0,
+			want:      "a | b      | header\n===========|=======\nlong value | x",
+		},
+		{
+			name:      "After passthrough line spans every column",
+			input:     "title\nname:john\nage:30",
+			skip:      []int{0},
+			afterLine: 0,
+			want:      "title\n=====|=====\nname | john\nage  | 30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := ParseTable(tt.input, ":")
+			table.Skip(tt.skip...)
+			table.InsertSeparator(tt.afterLine, "=")
+
+			if got := renderText(table, "|"); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableSkipMatch(t *testing.T) {
+	table, _ := ParseTable("name:john\n# a very long comment: here\nage:30", ":")
+	table.SkipMatch(regexp.MustCompile(`^#`))
+
+	want := "name | john\n# a very long comment: here\nage  | 30"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestTablePassUndelimited(t *testing.T) {
+	table, _ := ParseTable("A LONG TITLE LINE\nname:john\nage:30\nnickname:\n'a:b'", ":")
+	table.PassUndelimited()
+
+	want := "A LONG TITLE LINE\nname     | john\nage      | 30\nnickname\n'a:b'"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+
+	table, _ = ParseTable("hello\nworld", ":")
+	table.PassUndelimited()
+	if table.Rows[0].Kind != RowData {
+		t.Errorf("Kind = %v, want data when no line has the delimiter", table.Rows[0].Kind)
+	}
+}
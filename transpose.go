@@ -0,0 +1,31 @@
+package vsf
+
+// Transpose swaps the rows and columns of the table: the n-th cells of
+// the header, data and footer rows become the n-th row, so a header
+// becomes the first column. Short rows count as empty cells. The other
+// rows, like passthrough lines and separators, have no column to go to
+// and are dropped, as are hidden keys.
+func (t *Table) Transpose() {
+	var (
+		columns [][]string
+		n       int
+	)
+	for _, row := range t.Rows {
+		if row.aligned() {
+			columns = append(columns, row.Cells)
+			n = max(n, len(row.Cells))
+		}
+	}
+
+	rows := make([]Row, n)
+	for i := range rows {
+		cells := make([]string, len(columns))
+		for j, column := range columns {
+			if i < len(column) {
+				cells[j] = column[i]
+			}
+		}
+		rows[i] = Row{Kind: RowData, Cells: cells, Line: i, Record: i}
+	}
+	t.Rows = rows
+}
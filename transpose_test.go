@@ -0,0 +1,18 @@
+package vsf
+
+import "testing"
+
+func TestTableTranspose(t *testing.T) {
+	table, _ := ParseTable("name:age:city\n# staff\njohn:30\namy:25:rome", ":")
+	table.MarkHeader(1)
+	table.Skip(1)
+	table.HeaderMode = HeaderAligned
+	table.Transpose()
+
+	want := "name | john | amy\n" +
+		"age  | 30   | 25\n" +
+		"city |      | rome"
+	if got := renderText(table, "|"); got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package vsf
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date and time formats recognised in cells.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"02/01/2006",
+	"Jan 2 2006",
+	"2 Jan 2006",
+	"15:04:05",
+	"15:04",
+}
+
+// parseNumber parses a cell as a number, allowing surrounding blanks,
+// thousands separators and a trailing percent sign.
+func parseNumber(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	cell = strings.TrimSuffix(cell, "%")
+	cell = strings.ReplaceAll(cell, ",", "")
+	if cell == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(cell, 64)
+	return n, err == nil
+}
+
+// parseDate parses a cell with the first matching layout of dateLayouts.
+func parseDate(cell string) (time.Time, bool) {
+	cell = strings.TrimSpace(cell)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, cell); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isTyped reports whether a cell holds a number or a date.
+func isTyped(cell string) bool {
+	if _, ok := parseNumber(cell); ok {
+		return true
+	}
+	_, ok := parseDate(cell)
+	return ok
+}
@@ -1,7 +1,6 @@
 package vsf
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -48,6 +47,13 @@ func computeMaxLengths(rows [][]string) []int {
 //	// Returns: [`"hello,world"`, "foo", `"bar,baz"`]
 //	// Works with both single and double quotes
 func ParseLine(line, delimiter string) []string {
+	cells, _ := parseLine(line, delimiter)
+	return cells
+}
+
+// parseLine is ParseLine, also reporting whether line holds the
+// delimiter outside quotes, even when only empty cells follow it.
+func parseLine(line, delimiter string) (cells []string, delimited bool) {
 	line = strings.TrimSpace(line)
 	var (
 		result   []string
@@ -70,6 +76,7 @@ func ParseLine(line, delimiter string) []string {
 			result = append(result, strings.TrimSpace(current.String()))
 			current.Reset()
 			i += len(delimiter)
+			delimited = true
 			continue
 		}
 
@@ -81,7 +88,7 @@ func ParseLine(line, delimiter string) []string {
 		result = append(result, strings.TrimSpace(current.String()))
 	}
 
-	return result
+	return result, delimited
 }
 
 // Format formats input text by aligning columns based on a delimiter.
@@ -101,7 +108,7 @@ func ParseLine(line, delimiter string) []string {
 //	Format("name:john\nage:30\ncity:new york", ":", "")
 //	// Output: "name : john\nage  : 30\ncity : new york"
 func Format(input, delimiter, outputDelimiter string) (string, error) {
-	return formatColumns(input, delimiter, outputDelimiter, nil)
+	return format(input, WithDelimiter(delimiter), WithOutputDelimiter(outputDelimiter))
 }
 
 // FormatWithSeparator formats input text and adds a separator line after the specified line.
@@ -123,12 +130,38 @@ func Format(input, delimiter, outputDelimiter string) (string, error) {
 //	FormatWithSeparator("Index:Directory\n5:/path\n0:/short", ":", "", 0, "-")
 //	// Output: "Index : Directory\n------:---------\n5     : /path\n0     : /short"
 func FormatWithSeparator(input, delimiter, outputDelimiter string, afterLine int, sepChar string) (string, error) {
-	formatted, err := formatColumns(input, delimiter, outputDelimiter, nil)
-	if err != nil {
-		return "", err
+	opts := []Option{WithDelimiter(delimiter), WithOutputDelimiter(outputDelimiter)}
+	if afterLine >= 0 {
+		opts = append(opts, WithSeparator(afterLine, sepChar))
 	}
+	return format(input, opts...)
+}
 
-	return insertSeparatorAfterLine(formatted, afterLine, outputDelimiter, sepChar), nil
+// FormatWithHeader formats input text while keeping the first headerLines
+// lines as they are. Header lines still count towards the column widths,
+// so the body lines up with them.
+//
+// Parameters:
+//   - input: The input string to be formatted
+//   - delimiter: The delimiter used to split each line into columns
+//   - outputDelimiter: The delimiter to use in the output. If empty, uses input delimiter
+//   - headerLines: Number of leading lines to keep as header
+//
+// Returns:
+//   - A formatted string with aligned columns below the header
+//   - An error if the input is empty
+//
+// Example:
+//
+//	FormatWithHeader("name,age,city\njohn,30,nyc\namy,25,rome", ",", "|", 1)
+//	// Output: "name,age,city\njohn | 30  | nyc\namy  | 25  | rome"
+func FormatWithHeader(input, delimiter, outputDelimiter string, headerLines int) (string, error) {
+	return format(input,
+		WithDelimiter(delimiter),
+		WithOutputDelimiter(outputDelimiter),
+		WithHeaderLines(headerLines),
+		WithHeaderMode(HeaderVerbatim),
+	)
 }
 
 // FormatSkipLines formats input text while skipping certain lines from width calculations.
@@ -138,7 +171,8 @@ func FormatWithSeparator(input, delimiter, outputDelimiter string, afterLine int
 //   - input: The input string to be formatted
 //   - delimiter: The delimiter used to split each line into columns
 //   - outputDelimiter: The delimiter to use in the output. If empty, uses input delimiter
-//   - skipLines: Slice of line numbers (0-based) to skip from width calculations
+//   - skipLines: Slice of line numbers (0-based) to skip from width calculations.
+//     Negative numbers are ignored, use New with WithSkipLines to count from the end
 //
 // Returns:
 //   - A formatted string with aligned columns
@@ -150,112 +184,25 @@ func FormatWithSeparator(input, delimiter, outputDelimiter string, afterLine int
 //	// Output: "name : john\n----:----\nage  : 30"
 //	// Line 1 (----:----) doesn't affect column widths
 func FormatSkipLines(input, delimiter, outputDelimiter string, skipLines []int) (string, error) {
-	return formatColumns(input, delimiter, outputDelimiter, skipLines)
-}
-
-// formatColumns is the core formatting function used by all public functions
-func formatColumns(input, delimiter, outputDelimiter string, skipLines []int) (string, error) {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return "", fmt.Errorf("empty input")
-	}
-
-	lines := strings.Split(input, "\n")
-
-	// Create skip map for O(1) lookup
-	skipMap := make(map[int]bool)
-	for _, lineNum := range skipLines {
-		if lineNum >= 0 && lineNum < len(lines) {
-			skipMap[lineNum] = true
-		}
-	}
-
-	// Parse all lines, but only use non-skipped lines for width calculation
-	allSegments := make([][]string, len(lines))
-	widthCalcSegments := make([][]string, 0)
-
-	for i, line := range lines {
-		parsed := ParseLine(line, delimiter)
-		allSegments[i] = parsed
-
-		// Only include in width calculation if not skipped
-		if !skipMap[i] {
-			widthCalcSegments = append(widthCalcSegments, parsed)
+	var lines []int
+	for _, line := range skipLines {
+		if line >= 0 {
+			lines = append(lines, line)
 		}
 	}
-
-	// Calculate max lengths only from non-skipped lines
-	maxLengths := computeMaxLengths(widthCalcSegments)
-
-	if outputDelimiter == "" {
-		outputDelimiter = delimiter
-	}
-
-	var output strings.Builder
-	for i, row := range allSegments {
-		if skipMap[i] {
-			// For skipped lines, output as-is
-			output.WriteString(lines[i])
-		} else {
-			// For normal lines, format with alignment
-			for colIndex, cell := range row {
-				output.WriteString(cell)
-				if colIndex < len(row)-1 {
-					// Ensure we don't go out of bounds on maxLengths
-					padding := 0
-					if colIndex < len(maxLengths) {
-						padding = maxLengths[colIndex] - len(cell)
-					}
-					output.WriteString(strings.Repeat(" ", padding))
-					output.WriteString(" " + outputDelimiter + " ")
-				}
-			}
-		}
-
-		if i < len(allSegments)-1 {
-			output.WriteString("\n")
-		}
-	}
-
-	return output.String(), nil
-}
-
-// insertSeparatorAfterLine adds a separator line after the specified line number
-func insertSeparatorAfterLine(formatted string, afterLine int, outputDelimiter, sepChar string) string {
-	lines := strings.Split(formatted, "\n")
-
-	if afterLine < 0 || afterLine >= len(lines) {
-		return formatted // Invalid line number, return unchanged
-	}
-
-	// Generate separator based on the target line structure
-	separatorLine := generateSeparatorFromLine(lines[afterLine], outputDelimiter, sepChar)
-
-	// Insert separator after specified line
-	result := make([]string, 0, len(lines)+1)
-	for i, line := range lines {
-		result = append(result, line)
-		if i == afterLine {
-			result = append(result, separatorLine)
-		}
-	}
-
-	return strings.Join(result, "\n")
+	return format(input,
+		WithDelimiter(delimiter),
+		WithOutputDelimiter(outputDelimiter),
+		WithSkipLines(lines...),
+	)
 }
 
-// generateSeparatorFromLine creates a separator that matches the structure of a formatted line
-func generateSeparatorFromLine(formattedLine, outputDelimiter, sepChar string) string {
-	if outputDelimiter == "" {
-		outputDelimiter = ":"
-	}
-
-	delimiterPattern := " " + outputDelimiter + " "
-	parts := strings.Split(formattedLine, delimiterPattern)
-
-	var separatorParts []string
-	for _, part := range parts {
-		separatorParts = append(separatorParts, strings.Repeat(sepChar, len(part)))
+// format formats input with a one-off Formatter. It backs the positional
+// Format functions, kept for compatibility with New.
+func format(input string, opts ...Option) (string, error) {
+	f, err := New(opts...)
+	if err != nil {
+		return "", err
 	}
-
-	return strings.Join(separatorParts, strings.Repeat(sepChar, 1)+outputDelimiter+strings.Repeat(sepChar, 1))
+	return f.Format(input)
 }
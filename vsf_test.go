@@ -327,3 +327,73 @@ func TestParseLine(t *testing.T) {
 	}
 }
 
+func TestFormatWithHeader(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		delimiter       string
+		outputDelimiter string
+		headerLines     int
+		want            string
+		wantErr         bool
+	}{
+		{
+			name:            "CSV with one header line",
+			input:           "name,age,city\njohn,30,nyc\namy,25,rome",
+			delimiter:       ",",
+			outputDelimiter: "|",
+			headerLines:     1,
+			want:            "name,age,city\njohn | 30  | nyc\namy  | 25  | rome",
+			wantErr:         false,
+		},
+		{
+			name:            "Two header lines",
+			input:           "KEY:VALUE\n---:-----\nname:john\nage:30",
+			delimiter:       ":",
+			outputDelimiter: "",
+			headerLines:     2,
+			want:            "KEY:VALUE\n---:-----\nname : john\nage  : 30",
+			wantErr:         false,
+		},
+		{
+			name:            "No header lines (same as Format)",
+			input:           "name:john\nage:30",
+			delimiter:       ":",
+			outputDelimiter: "",
+			headerLines:     0,
+			want:            "name : john\nage  : 30",
+			wantErr:         false,
+		},
+		{
+			name:            "More header lines than input",
+			input:           "name:john\nage:30",
+			delimiter:       ":",
+			outputDelimiter: "",
+			headerLines:     5,
+			want:            "name:john\nage:30",
+			wantErr:         false,
+		},
+		{
+			name:            "Empty input",
+			input:           "",
+			delimiter:       ":",
+			outputDelimiter: "",
+			headerLines:     1,
+			want:            "",
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatWithHeader(tt.input, tt.delimiter, tt.outputDelimiter, tt.headerLines)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatWithHeader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FormatWithHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}